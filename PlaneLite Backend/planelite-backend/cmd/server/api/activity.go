@@ -0,0 +1,12 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/activity"
+)
+
+// RegisterActivity registers the workspace activity feed. Uses Auth + WorkspaceAccess.
+func RegisterActivity(mux *http.ServeMux, h *activity.Handler, mw Middleware) {
+	mux.Handle("GET /workspaces/{id}/activity", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/attachment"
+)
+
+// RegisterAttachment registers task attachment routes. Uses Auth + WorkspaceAccess.
+func RegisterAttachment(mux *http.ServeMux, h *attachment.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/attachments", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Upload))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/attachments", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/attachments/{aid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Download))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/attachments/{aid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/comment"
+)
+
+// RegisterComment registers task comment routes. Uses Auth + WorkspaceAccess.
+func RegisterComment(mux *http.ServeMux, h *comment.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/comments", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/comments", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/comments/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/comments/{cid}/replies", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListReplies))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/tasks/{tid}/comments/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/comments/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/cycle"
+)
+
+// RegisterCycle registers project cycle (sprint) routes. Uses Auth + WorkspaceAccess.
+func RegisterCycle(mux *http.ServeMux, h *cycle.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/cycles", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/cycles", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/cycles/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/cycles/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/cycles/{cid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/cycles/{cid}/tasks", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.AddTasks))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/cycles/{cid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.RemoveTask))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/cycles/{cid}/close", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Close))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/cycles/{cid}/scope", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListScope))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/cycles/{cid}/burndown", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Burndown))))
+}
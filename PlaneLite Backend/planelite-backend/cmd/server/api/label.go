@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/label"
+)
+
+// RegisterLabel registers label routes under workspaces. Uses Auth + WorkspaceAccess.
+func RegisterLabel(mux *http.ServeMux, h *label.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/labels", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/labels", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("PATCH /workspaces/{id}/labels/{lid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/labels/{lid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+}
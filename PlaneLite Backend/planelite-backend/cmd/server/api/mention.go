@@ -0,0 +1,12 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/mention"
+)
+
+// RegisterMention registers the current user's mention inbox. Uses Auth.
+func RegisterMention(mux *http.ServeMux, h *mention.Handler, mw Middleware) {
+	mux.Handle("GET /me/mentions", mw.Auth(http.HandlerFunc(h.ListMine)))
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/milestone"
+)
+
+// RegisterMilestone registers milestone and roadmap routes. Uses Auth + WorkspaceAccess.
+func RegisterMilestone(mux *http.ServeMux, h *milestone.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/milestones", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/milestones", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("GET /workspaces/{id}/milestones/{msid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
+	mux.Handle("PATCH /workspaces/{id}/milestones/{msid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/milestones/{msid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("GET /workspaces/{id}/roadmap", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Roadmap))))
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/module"
+)
+
+// RegisterModule registers project module (epic) routes. Uses Auth + WorkspaceAccess.
+func RegisterModule(mux *http.ServeMux, h *module.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/modules", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/modules", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/modules/{mid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/modules/{mid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/modules/{mid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/modules/{mid}/tasks", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.AddTasks))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/modules/{mid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.RemoveTask))))
+}
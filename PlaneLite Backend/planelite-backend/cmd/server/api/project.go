@@ -11,4 +11,5 @@ func RegisterProject(mux *http.ServeMux, h *project.Handler, mw Middleware) {
 	mux.Handle("POST /workspaces/{id}/projects", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
 	mux.Handle("GET /workspaces/{id}/projects", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListByWorkspace))))
 	mux.Handle("GET /workspaces/{id}/projects/{pid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
 }
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/search"
+)
+
+// RegisterSearch registers workspace search. Uses Auth + WorkspaceAccess.
+func RegisterSearch(mux *http.ServeMux, h *search.Handler, mw Middleware) {
+	mux.Handle("GET /workspaces/{id}/search", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Search))))
+}
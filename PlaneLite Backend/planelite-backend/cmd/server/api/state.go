@@ -0,0 +1,19 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/state"
+)
+
+// RegisterState registers workflow state and transition routes under projects. Uses Auth + WorkspaceAccess.
+func RegisterState(mux *http.ServeMux, h *state.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/states", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/states", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/states/reorder", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Reorder))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/states/{sid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/states/{sid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/transitions", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Graph))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/transitions", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.CreateTransition))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/transitions/{trid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.DeleteTransition))))
+}
@@ -10,7 +10,29 @@ import (
 func RegisterTask(mux *http.ServeMux, h *task.Handler, mw Middleware) {
 	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
 	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListByProject))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/bulk", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Bulk))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/trash", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListTrash))))
 	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByID))))
 	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
 	mux.Handle("PUT /workspaces/{id}/projects/{pid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/restore", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Restore))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/archive", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Archive))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/unarchive", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Unarchive))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/move", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Move))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/copy", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Copy))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/tasks/{tid}/status", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.UpdateStatus))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/tasks/{tid}/priority", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.UpdatePriority))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/assignees", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Assign))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/assignees/{uid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Unassign))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/children", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListChildren))))
+	mux.Handle("PUT /workspaces/{id}/projects/{pid}/tasks/{tid}/parent", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.SetParent))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/relations", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.AddRelation))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/relations", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListRelations))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/relations/{rid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.RemoveRelation))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/labels", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.AddLabel))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/labels/{lid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.RemoveLabel))))
+	mux.Handle("GET /workspaces/{id}/tasks/mine", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListMine))))
+	mux.Handle("GET /workspaces/{id}/tasks/overdue", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.ListOverdue))))
+	mux.Handle("GET /workspaces/{id}/tasks/{key}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.GetByKey))))
 }
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"planelite-backend/internal/worklog"
+)
+
+// RegisterWorklog registers time tracking routes: worklogs and timers on tasks, time totals and
+// timesheets. Uses Auth + WorkspaceAccess.
+func RegisterWorklog(mux *http.ServeMux, h *worklog.Handler, mw Middleware) {
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/worklogs", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Create))))
+	mux.Handle("GET /workspaces/{id}/projects/{pid}/tasks/{tid}/worklogs", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.List))))
+	mux.Handle("PATCH /workspaces/{id}/projects/{pid}/tasks/{tid}/worklogs/{wid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Update))))
+	mux.Handle("DELETE /workspaces/{id}/projects/{pid}/tasks/{tid}/worklogs/{wid}", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Delete))))
+	mux.Handle("POST /workspaces/{id}/projects/{pid}/tasks/{tid}/timer", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.StartTimer))))
+	mux.Handle("GET /workspaces/{id}/timer", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.CurrentTimer))))
+	mux.Handle("POST /workspaces/{id}/timer/stop", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.StopTimer))))
+	mux.Handle("GET /workspaces/{id}/time", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Summary))))
+	mux.Handle("GET /workspaces/{id}/timesheets", mw.Auth(mw.WorkspaceAccess(http.HandlerFunc(h.Timesheet))))
+}
@@ -11,15 +11,26 @@ import (
 
 	"planelite-backend/cmd/server/api"
 	"planelite-backend/internal/activity"
+	"planelite-backend/internal/attachment"
 	"planelite-backend/internal/auth"
+	"planelite-backend/internal/comment"
 	"planelite-backend/internal/common"
 	"planelite-backend/internal/config"
+	"planelite-backend/internal/cycle"
+	"planelite-backend/internal/label"
+	"planelite-backend/internal/mention"
 	"planelite-backend/internal/middleware"
+	"planelite-backend/internal/milestone"
+	"planelite-backend/internal/module"
 	"planelite-backend/internal/notification"
 	"planelite-backend/internal/notification/providers"
 	"planelite-backend/internal/project"
+	"planelite-backend/internal/search"
+	"planelite-backend/internal/state"
+	"planelite-backend/internal/storage"
 	"planelite-backend/internal/task"
 	"planelite-backend/internal/user"
+	"planelite-backend/internal/worklog"
 	"planelite-backend/internal/workspace"
 )
 
@@ -46,7 +57,12 @@ func main() {
 		_ = client.Disconnect(context.Background())
 	}()
 
+	common.SetCursorKey([]byte(cfg.CursorSecret))
+
 	db := client.Database(cfg.DBName)
+	if err := config.EnsureRequiredIndexes(context.Background(), db); err != nil {
+		log.Fatalf("ensure required indexes: %v", err)
+	}
 	if err := config.EnsureIndexes(context.Background(), db); err != nil {
 		log.Printf("warning: ensure indexes: %v", err)
 	}
@@ -57,24 +73,79 @@ func main() {
 	membershipRepo := workspace.NewMembershipRepository(db)
 	projectRepo := project.NewRepository(db)
 	taskRepo := task.NewRepository(db)
+	historyRepo := task.NewHistoryRepository(db)
+	relationRepo := task.NewRelationRepository(db)
+	stateRepo := state.NewRepository(db)
+	transitionRepo := state.NewTransitionRepository(db)
+	labelRepo := label.NewRepository(db)
+	commentRepo := comment.NewRepository(db)
+	mentionRepo := mention.NewRepository(db)
+	attachmentRepo := attachment.NewRepository(db)
+	worklogRepo := worklog.NewRepository(db)
+	cycleRepo := cycle.NewRepository(db)
+	moduleRepo := module.NewRepository(db)
+	milestoneRepo := milestone.NewRepository(db)
+
+	blobs, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("storage: %v", err)
+	}
+
+	inApp := providers.NewInAppProvider()
+	whatsApp := providers.NewWhatsAppProvider()
+	notificationSvc := notification.NewService(inApp, whatsApp)
 
 	userSvc := user.NewService(userRepo)
 	authSvc := auth.NewService(userSvc, cfg)
 	workspaceSvc := workspace.NewService(workspaceRepo, membershipRepo)
 	projectSvc := project.NewService(projectRepo)
-	taskSvc := task.NewService(taskRepo)
+	stateSvc := state.NewService(stateRepo, transitionRepo)
+	labelSvc := label.NewService(labelRepo)
+	mentionSvc := mention.NewService(mentionRepo, userSvc, workspaceSvc, notificationSvc)
 	activitySvc := activity.NewService(db)
-	_ = activitySvc
+	taskSvc := task.NewService(taskRepo, historyRepo, relationRepo, projectSvc, workspaceSvc, stateSvc, labelSvc, mentionSvc, activitySvc)
+	taskSvc.ParentCompletion = task.ParentCompletion(cfg.TaskParentCompletion)
+	projectSvc.Tasks = taskSvc
+	stateSvc.Tasks = taskSvc
+	labelSvc.Tasks = taskSvc
+	commentSvc := comment.NewService(commentRepo, taskSvc, activitySvc, mentionSvc)
+	attachmentSvc := attachment.NewService(attachmentRepo, blobs, taskSvc, cfg.AttachmentMaxBytes, cfg.AttachmentTypes)
+	worklogSvc := worklog.NewService(worklogRepo, taskSvc, projectSvc)
+	cycleSvc := cycle.NewService(cycleRepo, taskSvc, projectSvc)
+	moduleSvc := module.NewService(moduleRepo, taskSvc, projectSvc, workspaceSvc)
+	milestoneSvc := milestone.NewService(milestoneRepo, taskSvc, moduleSvc, projectSvc)
+	taskSvc.Dependents = []task.Dependent{attachmentSvc, commentSvc, mentionSvc, worklogSvc, cycleSvc, milestoneSvc}
+	moduleSvc.Dependents = []module.Dependent{milestoneSvc}
+	taskSvc.Cycles = cycleSvc
+	taskSvc.Comments = commentSvc
+	taskSvc.Attachments = attachmentSvc
+	searchSvc := search.NewService(search.NewMongo(db))
+
+	// Give data created before project identifiers and task keys existed its keys.
+	if err := projectSvc.BackfillIdentifiers(context.Background()); err != nil {
+		log.Printf("warning: backfill project identifiers: %v", err)
+	} else if err := taskSvc.BackfillKeys(context.Background()); err != nil {
+		log.Printf("warning: backfill task keys: %v", err)
+	}
 
-	inApp := providers.NewInAppProvider()
-	whatsApp := providers.NewWhatsAppProvider()
-	_ = notification.NewService(inApp, whatsApp)
+	go purgeTrash(taskSvc, time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
 
 	authHandler := auth.NewHandler(authSvc, cfg)
 	userHandler := user.NewHandler(userSvc)
 	workspaceHandler := workspace.NewHandler(workspaceSvc)
 	projectHandler := project.NewHandler(projectSvc)
 	taskHandler := task.NewHandler(taskSvc)
+	stateHandler := state.NewHandler(stateSvc, projectSvc)
+	labelHandler := label.NewHandler(labelSvc)
+	commentHandler := comment.NewHandler(commentSvc)
+	mentionHandler := mention.NewHandler(mentionSvc)
+	attachmentHandler := attachment.NewHandler(attachmentSvc)
+	activityHandler := activity.NewHandler(activitySvc)
+	searchHandler := search.NewHandler(searchSvc)
+	worklogHandler := worklog.NewHandler(worklogSvc)
+	cycleHandler := cycle.NewHandler(cycleSvc)
+	moduleHandler := module.NewHandler(moduleSvc)
+	milestoneHandler := milestone.NewHandler(milestoneSvc)
 
 	authMW := middleware.Auth(authSvc)
 	adminOnly := middleware.RequireRole(common.RoleAdmin)
@@ -103,6 +174,17 @@ func main() {
 	api.RegisterWorkspace(mux, workspaceHandler, mw)
 	api.RegisterProject(mux, projectHandler, mw)
 	api.RegisterTask(mux, taskHandler, mw)
+	api.RegisterState(mux, stateHandler, mw)
+	api.RegisterLabel(mux, labelHandler, mw)
+	api.RegisterComment(mux, commentHandler, mw)
+	api.RegisterMention(mux, mentionHandler, mw)
+	api.RegisterAttachment(mux, attachmentHandler, mw)
+	api.RegisterActivity(mux, activityHandler, mw)
+	api.RegisterSearch(mux, searchHandler, mw)
+	api.RegisterWorklog(mux, worklogHandler, mw)
+	api.RegisterCycle(mux, cycleHandler, mw)
+	api.RegisterModule(mux, moduleHandler, mw)
+	api.RegisterMilestone(mux, milestoneHandler, mw)
 
 	port := cfg.Port
 	if port == "" {
@@ -113,3 +195,20 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// purgeTrash permanently deletes tasks that have been in the trash longer than retention, once at
+// startup and then hourly.
+func purgeTrash(taskSvc *task.Service, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		n, err := taskSvc.PurgeTrash(context.Background(), time.Now().Add(-retention))
+		if n > 0 {
+			log.Printf("purged %d task(s) from the trash", n)
+		}
+		if err != nil {
+			log.Printf("warning: purge trash: %v", err)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// List handles GET /workspaces/:id/activity?project=&task= (newest first, paged; see common.ParseListParams).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var f Filter
+	for param, dst := range map[string]**primitive.ObjectID{"project": &f.ProjectID, "task": &f.TaskID} {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		*dst = &id
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.List(r.Context(), wsID, f, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
@@ -9,10 +9,13 @@ import (
 type ActivityKind string
 
 const (
-	KindTaskCreated   ActivityKind = "task_created"
-	KindTaskUpdated   ActivityKind = "task_updated"
-	KindTaskCompleted ActivityKind = "task_completed"
-	KindMemberAdded   ActivityKind = "member_added"
+	KindTaskCreated    ActivityKind = "task_created"
+	KindTaskUpdated    ActivityKind = "task_updated"
+	KindTaskCompleted  ActivityKind = "task_completed"
+	KindMemberAdded    ActivityKind = "member_added"
+	KindCommentAdded   ActivityKind = "comment_added"
+	KindCommentEdited  ActivityKind = "comment_edited"
+	KindCommentDeleted ActivityKind = "comment_deleted"
 )
 
 type Activity struct {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
 )
 
 // Service records activity for audit/feed. Stub: can be backed by a collection later.
@@ -30,3 +32,21 @@ func (s *Service) Record(ctx context.Context, workspaceID, projectID, taskID, us
 	_, err := s.col.InsertOne(ctx, a)
 	return err
 }
+
+// Filter narrows an activity listing to a project or task; nil means any.
+type Filter struct {
+	ProjectID *primitive.ObjectID
+	TaskID    *primitive.ObjectID
+}
+
+// List returns one page of the workspace's activity, newest first.
+func (s *Service) List(ctx context.Context, workspaceID primitive.ObjectID, f Filter, p common.ListParams) (*common.ListPage[*Activity], error) {
+	filter := bson.M{"workspace_id": workspaceID}
+	if f.ProjectID != nil {
+		filter["project_id"] = *f.ProjectID
+	}
+	if f.TaskID != nil {
+		filter["task_id"] = *f.TaskID
+	}
+	return common.FindPage[Activity](ctx, s.col, filter, bson.D{{Key: "_id", Value: -1}}, p)
+}
@@ -0,0 +1,171 @@
+package attachment
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+// multipartMemory is how much of an upload is buffered in memory before spilling to a temp file.
+const multipartMemory = 8 << 20
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Upload handles POST /workspaces/:id/projects/:pid/tasks/:tid/attachments (multipart form, field "file").
+func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	// Leave room for the multipart envelope around the file itself.
+	r.Body = http.MaxBytesReader(w, r.Body, h.svc.MaxBytes()+1<<20)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			common.JSON(w, http.StatusRequestEntityTooLarge, common.ErrorResp{Error: "file too large"})
+			return
+		}
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	defer file.Close()
+	a, err := h.svc.Upload(r.Context(), wsID, pid, tid, userID, UploadInput{
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, a)
+}
+
+// List handles GET /workspaces/:id/projects/:pid/tasks/:tid/attachments.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	list, err := h.svc.List(r.Context(), wsID, pid, tid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, list)
+}
+
+// Download handles GET /workspaces/:id/projects/:pid/tasks/:tid/attachments/:aid and streams the file.
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	aid, err := primitive.ObjectIDFromHex(r.PathValue("aid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	a, rc, err := h.svc.Open(r.Context(), wsID, pid, tid, aid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	defer rc.Close()
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, rc)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/attachments/:aid. Uploader or ADMIN.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	aid, err := primitive.ObjectIDFromHex(r.PathValue("aid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, pid, tid, aid, userID, u.Role); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+func taskPath(r *http.Request) (wsID, pid, tid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if tid, err = primitive.ObjectIDFromHex(r.PathValue("tid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	return wsID, pid, tid, true
+}
@@ -0,0 +1,24 @@
+package attachment
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attachment is the metadata of a file attached to a task; the content lives in blob storage under StorageKey.
+type Attachment struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+
+	Filename    string `bson:"filename" json:"filename"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Size        int64  `bson:"size" json:"size"`
+	StorageKey  string `bson:"storage_key" json:"-"`
+
+	UploadedBy primitive.ObjectID `bson:"uploaded_by" json:"uploaded_by"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
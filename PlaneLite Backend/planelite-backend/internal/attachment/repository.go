@@ -0,0 +1,61 @@
+package attachment
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("attachments")}
+}
+
+// Create stores a; a.ID must already be set since it is part of the storage key.
+func (r *Repository) Create(ctx context.Context, a *Attachment) error {
+	_, err := r.col.InsertOne(ctx, a)
+	return err
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Attachment, error) {
+	var a Attachment
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListByTask returns the task's attachments, oldest first.
+func (r *Repository) ListByTask(ctx context.Context, taskID primitive.ObjectID) ([]*Attachment, error) {
+	cur, err := r.col.Find(ctx, bson.M{"task_id": taskID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Attachment
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = []*Attachment{}
+	}
+	return out, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// SetProject points all attachments of a task at the task's new project.
+func (r *Repository) SetProject(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"task_id": taskID}, bson.M{"$set": bson.M{"project_id": projectID}})
+	return err
+}
@@ -0,0 +1,232 @@
+package attachment
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/storage"
+)
+
+// TaskChecker verifies that a task exists in a project and workspace. Implemented by task.Service.
+type TaskChecker interface {
+	CheckInProject(ctx context.Context, projectID, id primitive.ObjectID) error
+	CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error
+}
+
+type Service struct {
+	repo     *Repository
+	blobs    storage.Blob
+	tasks    TaskChecker
+	maxBytes int64
+	types    []string
+}
+
+// NewService returns an attachment service accepting uploads up to maxBytes whose MIME type matches
+// one of types ("image/*" matches any image type).
+func NewService(repo *Repository, blobs storage.Blob, tasks TaskChecker, maxBytes int64, types []string) *Service {
+	return &Service{repo: repo, blobs: blobs, tasks: tasks, maxBytes: maxBytes, types: types}
+}
+
+// MaxBytes is the largest accepted upload.
+func (s *Service) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// UploadInput describes an uploaded file. ContentType is the client's claim; it is replaced by the
+// sniffed type when missing or generic.
+type UploadInput struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// Upload stores the file in blob storage and records it on the task.
+func (s *Service) Upload(ctx context.Context, workspaceID, projectID, taskID, uploadedBy primitive.ObjectID, in UploadInput) (*Attachment, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	name := cleanFilename(in.Filename)
+	if name == "" {
+		return nil, fmt.Errorf("%w: filename is required", common.ErrInvalidInput)
+	}
+	if in.Size <= 0 || in.Size > s.maxBytes {
+		return nil, fmt.Errorf("%w: file must be between 1 byte and %d bytes", common.ErrInvalidInput, s.maxBytes)
+	}
+	body := bufio.NewReaderSize(in.Body, 512)
+	contentType := mediaType(in.ContentType)
+	if contentType == "" || contentType == "application/octet-stream" {
+		head, _ := body.Peek(512)
+		contentType = mediaType(http.DetectContentType(head))
+	}
+	if !s.allowed(contentType) {
+		return nil, fmt.Errorf("%w: file type %s is not allowed", common.ErrInvalidInput, contentType)
+	}
+	a := &Attachment{
+		ID:          primitive.NewObjectID(),
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		Filename:    name,
+		ContentType: contentType,
+		Size:        in.Size,
+		UploadedBy:  uploadedBy,
+		CreatedAt:   time.Now(),
+	}
+	a.StorageKey = "tasks/" + taskID.Hex() + "/" + a.ID.Hex()
+	if err := s.blobs.Put(ctx, a.StorageKey, io.LimitReader(body, in.Size), in.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		_ = s.blobs.Delete(ctx, a.StorageKey)
+		return nil, err
+	}
+	return a, nil
+}
+
+// List returns the task's attachments.
+func (s *Service) List(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID) ([]*Attachment, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTask(ctx, taskID)
+}
+
+// Open returns the attachment and a reader for its content; the caller must close the reader.
+func (s *Service) Open(ctx context.Context, workspaceID, projectID, taskID, id primitive.ObjectID) (*Attachment, io.ReadCloser, error) {
+	a, err := s.find(ctx, workspaceID, projectID, taskID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.blobs.Get(ctx, a.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, rc, nil
+}
+
+// Delete removes an attachment and its blob. The uploader or a workspace ADMIN may delete.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, role common.Role) error {
+	a, err := s.find(ctx, workspaceID, projectID, taskID, id)
+	if err != nil {
+		return err
+	}
+	if a.UploadedBy != userID && role != common.RoleAdmin {
+		return common.ErrForbidden
+	}
+	if err := s.blobs.Delete(ctx, a.StorageKey); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// checkTask verifies that the task is in the project and the project in the workspace.
+func (s *Service) checkTask(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID) error {
+	if err := s.tasks.CheckInProject(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	return s.tasks.CheckInWorkspace(ctx, workspaceID, taskID)
+}
+
+// find returns an attachment of the task, checking the task, project and workspace of the route.
+func (s *Service) find(ctx context.Context, workspaceID, projectID, taskID, id primitive.ObjectID) (*Attachment, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil || a.TaskID != taskID || a.ProjectID != projectID || a.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	return a, nil
+}
+
+// DeleteByTask removes all attachments of a deleted task, blobs first so a failure can be retried.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	list, err := s.repo.ListByTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, a := range list {
+		if err := s.blobs.Delete(ctx, a.StorageKey); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveTask follows a task into another project of the same workspace. Blob keys only depend on
+// the task, so the stored files stay where they are.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	return s.repo.SetProject(ctx, taskID, projectID)
+}
+
+// CopyTask copies the attachments of one task, blobs included, onto a copy of it in projectID.
+func (s *Service) CopyTask(ctx context.Context, fromTaskID, toTaskID, projectID primitive.ObjectID) error {
+	list, err := s.repo.ListByTask(ctx, fromTaskID)
+	if err != nil {
+		return err
+	}
+	for _, a := range list {
+		dup := *a
+		dup.ID = primitive.NewObjectID()
+		dup.ProjectID = projectID
+		dup.TaskID = toTaskID
+		dup.StorageKey = "tasks/" + toTaskID.Hex() + "/" + dup.ID.Hex()
+		if err := s.copyBlob(ctx, a.StorageKey, &dup); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, &dup); err != nil {
+			_ = s.blobs.Delete(ctx, dup.StorageKey)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) copyBlob(ctx context.Context, from string, to *Attachment) error {
+	rc, err := s.blobs.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return s.blobs.Put(ctx, to.StorageKey, rc, to.Size, to.ContentType)
+}
+
+func (s *Service) allowed(contentType string) bool {
+	for _, t := range s.types {
+		if t == contentType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType strips parameters such as charset from a Content-Type value.
+func mediaType(v string) string {
+	mt, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// cleanFilename drops any directory part a client may send.
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
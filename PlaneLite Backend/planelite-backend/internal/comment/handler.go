@@ -0,0 +1,227 @@
+package comment
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST .../tasks/:tid/comments.
+type CreateRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"` // optional; makes the comment a reply
+}
+
+// UpdateRequest is the JSON body for PATCH .../tasks/:tid/comments/:cid.
+type UpdateRequest struct {
+	Body string `json:"body"`
+}
+
+// Create handles POST /workspaces/:id/projects/:pid/tasks/:tid/comments.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	authorID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var parentID *primitive.ObjectID
+	if req.ParentID != "" {
+		id, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		parentID = &id
+	}
+	c, err := h.svc.Create(r.Context(), wsID, pid, tid, authorID, parentID, req.Body)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, c)
+}
+
+// List handles GET /workspaces/:id/projects/:pid/tasks/:tid/comments?cursor=&limit= (top-level comments).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, false)
+}
+
+// ListReplies handles GET /workspaces/:id/projects/:pid/tasks/:tid/comments/:cid/replies?cursor=&limit=.
+func (h *Handler) ListReplies(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, true)
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, replies bool) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var parentID *primitive.ObjectID
+	if replies {
+		cid, err := primitive.ObjectIDFromHex(r.PathValue("cid"))
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		parentID = &cid
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = common.DefaultPageSize
+	}
+	if limit > common.MaxPageSize {
+		limit = common.MaxPageSize
+	}
+	page, err := h.svc.List(r.Context(), wsID, pid, tid, parentID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// GetByID handles GET /workspaces/:id/projects/:pid/tasks/:tid/comments/:cid (includes edit history).
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	cid, err := primitive.ObjectIDFromHex(r.PathValue("cid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	c, err := h.svc.Get(r.Context(), wsID, pid, tid, cid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, c)
+}
+
+// Update handles PATCH /workspaces/:id/projects/:pid/tasks/:tid/comments/:cid. Author only.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	cid, err := primitive.ObjectIDFromHex(r.PathValue("cid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	c, err := h.svc.Edit(r.Context(), wsID, pid, tid, cid, userID, req.Body)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, c)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/comments/:cid. Author or ADMIN.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	cid, err := primitive.ObjectIDFromHex(r.PathValue("cid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, pid, tid, cid, userID, u.Role); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// taskPath parses the workspace, project and task IDs from the route.
+func taskPath(r *http.Request) (wsID, pid, tid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if tid, err = primitive.ObjectIDFromHex(r.PathValue("tid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	return wsID, pid, tid, true
+}
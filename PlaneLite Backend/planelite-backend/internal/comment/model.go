@@ -0,0 +1,48 @@
+package comment
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Edit is a previous version of a comment body, kept when the author edits it.
+type Edit struct {
+	Body     string    `bson:"body" json:"body"`
+	EditedAt time.Time `bson:"edited_at" json:"edited_at"`
+}
+
+// Comment is a message on a task. Replies point at the comment they answer via ParentID.
+// Deleted comments stay in place (so threads keep their shape) with the body hidden.
+type Comment struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID  `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID  `bson:"project_id" json:"project_id"`
+	TaskID      primitive.ObjectID  `bson:"task_id" json:"task_id"`
+	ParentID    *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+
+	AuthorID primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Body     string             `bson:"body" json:"body"`
+	Edits    []Edit             `bson:"edits" json:"edits"`
+
+	Deleted   bool       `bson:"deleted" json:"deleted"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// redact hides the content of a deleted comment.
+func (c *Comment) redact() {
+	if c.Deleted {
+		c.Body = ""
+		c.Edits = []Edit{}
+	}
+}
+
+// Page is one page of comments. NextCursor is empty on the last page.
+type Page struct {
+	Items      []*Comment `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
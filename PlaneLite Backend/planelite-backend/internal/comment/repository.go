@@ -0,0 +1,120 @@
+package comment
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("comments")}
+}
+
+func (r *Repository) Create(ctx context.Context, c *Comment) error {
+	doc := bson.M{
+		"workspace_id": c.WorkspaceID,
+		"project_id":   c.ProjectID,
+		"task_id":      c.TaskID,
+		"author_id":    c.AuthorID,
+		"body":         c.Body,
+		"edits":        c.Edits,
+		"deleted":      false,
+		"created_at":   c.CreatedAt,
+		"updated_at":   c.UpdatedAt,
+	}
+	if c.ParentID != nil {
+		doc["parent_id"] = *c.ParentID
+	}
+	result, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		c.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Comment, error) {
+	var c Comment
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByTask returns up to limit comments of the task in creation order, starting after the
+// given ID. A nil parentID lists top-level comments; otherwise replies to that comment.
+func (r *Repository) ListByTask(ctx context.Context, taskID primitive.ObjectID, parentID, after *primitive.ObjectID, limit int64) ([]*Comment, error) {
+	filter := bson.M{"task_id": taskID}
+	if parentID != nil {
+		filter["parent_id"] = *parentID
+	} else {
+		filter["parent_id"] = bson.M{"$exists": false}
+	}
+	if after != nil {
+		filter["_id"] = bson.M{"$gt": *after}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Comment
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAllByTask returns every comment of the task, replies included, in creation order.
+func (r *Repository) ListAllByTask(ctx context.Context, taskID primitive.ObjectID) ([]*Comment, error) {
+	cur, err := r.col.Find(ctx, bson.M{"task_id": taskID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Comment
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateBody replaces the body and appends the previous version to the edit history.
+func (r *Repository) UpdateBody(ctx context.Context, id primitive.ObjectID, body string, previous Edit) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":  bson.M{"body": body, "updated_at": previous.EditedAt},
+		"$push": bson.M{"edits": previous},
+	})
+	return err
+}
+
+func (r *Repository) SoftDelete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"deleted": true, "deleted_at": at, "updated_at": at},
+	})
+	return err
+}
+
+// DeleteByTask removes all comments of a task.
+func (r *Repository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.col.DeleteMany(ctx, bson.M{"task_id": taskID})
+	return err
+}
+
+// SetProject points all comments of a task at the task's new project.
+func (r *Repository) SetProject(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"task_id": taskID}, bson.M{"$set": bson.M{"project_id": projectID}})
+	return err
+}
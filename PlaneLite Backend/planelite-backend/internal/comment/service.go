@@ -0,0 +1,239 @@
+package comment
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/activity"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/mention"
+)
+
+// TaskChecker verifies that a task exists in a project and workspace. Implemented by task.Service.
+type TaskChecker interface {
+	CheckInProject(ctx context.Context, projectID, id primitive.ObjectID) error
+	CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error
+}
+
+type Service struct {
+	repo     *Repository
+	tasks    TaskChecker
+	activity *activity.Service
+	mentions *mention.Service
+}
+
+func NewService(repo *Repository, tasks TaskChecker, activitySvc *activity.Service, mentions *mention.Service) *Service {
+	return &Service{repo: repo, tasks: tasks, activity: activitySvc, mentions: mentions}
+}
+
+// Create adds a comment to a task, or a reply when parentID is set.
+func (s *Service) Create(ctx context.Context, workspaceID, projectID, taskID, authorID primitive.ObjectID, parentID *primitive.ObjectID, body string) (*Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, common.ErrInvalidInput
+	}
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		parent, err := s.repo.FindByID(ctx, *parentID)
+		if err != nil || parent.TaskID != taskID {
+			return nil, common.ErrInvalidInput
+		}
+	}
+	body, mentioned := s.mentions.Resolve(ctx, workspaceID, body)
+	now := time.Now()
+	c := &Comment{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		ParentID:    parentID,
+		AuthorID:    authorID,
+		Body:        body,
+		Edits:       []Edit{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	s.record(ctx, c, authorID, activity.KindCommentAdded)
+	if err := s.mentions.Sync(ctx, mentionRef(c, authorID), body, mentioned); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns a comment of the task, including its edit history.
+func (s *Service) Get(ctx context.Context, workspaceID, projectID, taskID, id primitive.ObjectID) (*Comment, error) {
+	c, err := s.find(ctx, workspaceID, projectID, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	c.redact()
+	return c, nil
+}
+
+// List returns a page of the task's top-level comments, or of replies when parentID is set.
+func (s *Service) List(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID, parentID *primitive.ObjectID, cursor string, limit int) (*Page, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	var after *primitive.ObjectID
+	if cursor != "" {
+		c, err := common.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &c.ID
+	}
+	// Fetch one extra to know whether another page exists.
+	list, err := s.repo.ListByTask(ctx, taskID, parentID, after, int64(limit)+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{Items: list}
+	if len(list) > limit {
+		page.Items = list[:limit]
+		page.NextCursor = common.EncodeCursor(common.Cursor{ID: page.Items[limit-1].ID})
+	}
+	if page.Items == nil {
+		page.Items = []*Comment{}
+	}
+	for _, c := range page.Items {
+		c.redact()
+	}
+	return page, nil
+}
+
+// Edit replaces the body of a comment. Only the author may edit; the old body is kept in Edits.
+func (s *Service) Edit(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, body string) (*Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, common.ErrInvalidInput
+	}
+	c, err := s.find(ctx, workspaceID, projectID, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Deleted {
+		return nil, common.ErrNotFound
+	}
+	if c.AuthorID != userID {
+		return nil, common.ErrForbidden
+	}
+	body, mentioned := s.mentions.Resolve(ctx, c.WorkspaceID, body)
+	if body == c.Body {
+		return c, nil
+	}
+	if err := s.repo.UpdateBody(ctx, id, body, Edit{Body: c.Body, EditedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	s.record(ctx, c, userID, activity.KindCommentEdited)
+	if err := s.mentions.Sync(ctx, mentionRef(c, userID), body, mentioned); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete soft-deletes a comment. The author or a workspace ADMIN may delete.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, role common.Role) error {
+	c, err := s.find(ctx, workspaceID, projectID, taskID, id)
+	if err != nil {
+		return err
+	}
+	if c.Deleted {
+		return common.ErrNotFound
+	}
+	if c.AuthorID != userID && role != common.RoleAdmin {
+		return common.ErrForbidden
+	}
+	if err := s.repo.SoftDelete(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	s.record(ctx, c, userID, activity.KindCommentDeleted)
+	return nil
+}
+
+// checkTask verifies that the task is in the project and the project in the workspace.
+func (s *Service) checkTask(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID) error {
+	if err := s.tasks.CheckInProject(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	return s.tasks.CheckInWorkspace(ctx, workspaceID, taskID)
+}
+
+// find returns a comment of the task, checking the task, project and workspace of the route.
+func (s *Service) find(ctx context.Context, workspaceID, projectID, taskID, id primitive.ObjectID) (*Comment, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil || c.TaskID != taskID || c.ProjectID != projectID || c.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	return c, nil
+}
+
+// DeleteByTask removes the comments of a deleted task.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return s.repo.DeleteByTask(ctx, taskID)
+}
+
+// MoveTask follows a task into another project of the same workspace.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	return s.repo.SetProject(ctx, taskID, projectID)
+}
+
+// CopyTask copies the comments of one task onto a copy of it in projectID, keeping authors, times and
+// threads. Deleted comments and edit histories are not copied; replies to a deleted comment become
+// top-level comments.
+func (s *Service) CopyTask(ctx context.Context, fromTaskID, toTaskID, projectID primitive.ObjectID) error {
+	list, err := s.repo.ListAllByTask(ctx, fromTaskID)
+	if err != nil {
+		return err
+	}
+	copied := make(map[primitive.ObjectID]primitive.ObjectID, len(list))
+	for _, c := range list {
+		if c.Deleted {
+			continue
+		}
+		dup := &Comment{
+			WorkspaceID: c.WorkspaceID,
+			ProjectID:   projectID,
+			TaskID:      toTaskID,
+			AuthorID:    c.AuthorID,
+			Body:        c.Body,
+			Edits:       []Edit{},
+			CreatedAt:   c.CreatedAt,
+			UpdatedAt:   c.UpdatedAt,
+		}
+		if c.ParentID != nil {
+			if parent, ok := copied[*c.ParentID]; ok {
+				dup.ParentID = &parent
+			}
+		}
+		if err := s.repo.Create(ctx, dup); err != nil {
+			return err
+		}
+		copied[c.ID] = dup.ID
+	}
+	return nil
+}
+
+// record writes an activity entry; failures are not surfaced since the comment change already succeeded.
+func (s *Service) record(ctx context.Context, c *Comment, userID primitive.ObjectID, kind activity.ActivityKind) {
+	if s.activity == nil {
+		return
+	}
+	_ = s.activity.Record(ctx, c.WorkspaceID, c.ProjectID, c.TaskID, userID, kind, map[string]any{
+		"comment_id": c.ID.Hex(),
+	})
+}
+
+func mentionRef(c *Comment, authorID primitive.ObjectID) mention.Ref {
+	id := c.ID
+	return mention.Ref{WorkspaceID: c.WorkspaceID, ProjectID: c.ProjectID, TaskID: c.TaskID, CommentID: &id, AuthorID: authorID}
+}
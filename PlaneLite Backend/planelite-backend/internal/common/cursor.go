@@ -0,0 +1,139 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// cursorKey signs cursors so clients cannot forge positions or smuggle values into queries.
+var cursorKey []byte
+
+// SetCursorKey sets the key cursors are signed with. Call once at startup.
+func SetCursorKey(key []byte) {
+	cursorKey = key
+}
+
+// Cursor marks a position in a sorted list: the sort-field values and _id of the last item returned.
+// The next page starts after it.
+type Cursor struct {
+	Sort   string             `bson:"s,omitempty"` // SortID of the ordering the cursor was issued for
+	Values []any              `bson:"v,omitempty"` // one per sort field, excluding the trailing _id
+	ID     primitive.ObjectID `bson:"i"`
+}
+
+// EncodeCursor returns an opaque, signed token for c.
+func EncodeCursor(c Cursor) string {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(data))
+}
+
+// DecodeCursor verifies and parses a token from EncodeCursor. Malformed or tampered cursors
+// return ErrBadRequest.
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return c, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return c, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, cursorMAC(data)) {
+		return c, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	if err := bson.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	return c, nil
+}
+
+func cursorMAC(data []byte) []byte {
+	h := hmac.New(sha256.New, cursorKey)
+	h.Write(data)
+	return h.Sum(nil)[:16]
+}
+
+// SortID identifies an ordering, so a cursor issued for one sort is not used with another.
+func SortID(sort bson.D) string {
+	parts := make([]string, len(sort))
+	for i, e := range sort {
+		parts[i] = fmt.Sprintf("%s:%v", e.Key, e.Value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// checkCursorSort returns ErrBadRequest unless c was issued for sort.
+func checkCursorSort(c Cursor, sort bson.D) error {
+	if c.Sort != SortID(sort) {
+		return fmt.Errorf("%w: cursor does not match the requested sort", ErrBadRequest)
+	}
+	return nil
+}
+
+// KeysetFilter selects documents that come after c in sort order. sort must end with _id, and
+// null or missing values sort first, as in Mongo.
+func KeysetFilter(sort bson.D, c Cursor) bson.M {
+	value := func(i int) any {
+		switch {
+		case sort[i].Key == "_id":
+			return c.ID
+		case i < len(c.Values):
+			return c.Values[i]
+		default:
+			return nil
+		}
+	}
+	var or []bson.M
+	for i, e := range sort {
+		cond := afterValue(value(i), e.Value == -1)
+		if cond == nil {
+			continue
+		}
+		branch := bson.M{e.Key: cond}
+		for j := 0; j < i; j++ {
+			branch[sort[j].Key] = value(j)
+		}
+		or = append(or, branch)
+	}
+	if len(or) == 0 {
+		return bson.M{"_id": bson.M{"$exists": false}} // nothing comes after
+	}
+	return bson.M{"$or": or}
+}
+
+// afterValue returns the condition for values strictly after v, or nil when none can be.
+func afterValue(v any, desc bool) any {
+	switch {
+	case v == nil && desc:
+		return nil
+	case v == nil:
+		return bson.M{"$ne": nil}
+	case desc:
+		// Below v, including null and missing values, which sort last in descending order.
+		return bson.M{"$not": bson.M{"$gte": v}}
+	default:
+		return bson.M{"$gt": v}
+	}
+}
+
+// SortValues returns the values of doc's sort fields, excluding _id, for building a cursor.
+func SortValues(doc bson.M, sort bson.D) []any {
+	var values []any
+	for _, e := range sort {
+		if e.Key != "_id" {
+			values = append(values, doc[e.Key])
+		}
+	}
+	return values
+}
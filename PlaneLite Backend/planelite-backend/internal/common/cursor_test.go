@@ -0,0 +1,70 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	SetCursorKey([]byte("test-key"))
+	sort := bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: 1}}
+	want := Cursor{Sort: SortID(sort), Values: []any{"high"}, ID: primitive.NewObjectID()}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if got.Sort != want.Sort || got.ID != want.ID {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if len(got.Values) != 1 || got.Values[0] != "high" {
+		t.Errorf("Values = %v, want [high]", got.Values)
+	}
+	if err := checkCursorSort(got, sort); err != nil {
+		t.Errorf("checkCursorSort: %v", err)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	SetCursorKey([]byte("test-key"))
+	token := EncodeCursor(Cursor{Values: []any{"low"}, ID: primitive.NewObjectID()})
+	payload, sig, _ := strings.Cut(token, ".")
+
+	other := EncodeCursor(Cursor{Values: []any{"urgent"}, ID: primitive.NewObjectID()})
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	for name, bad := range map[string]string{
+		"swapped payload": otherPayload + "." + sig,
+		"missing sig":     payload,
+		"garbled sig":     payload + ".!!",
+		"empty":           "",
+	} {
+		if _, err := DecodeCursor(bad); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("%s: err = %v, want ErrBadRequest", name, err)
+		}
+	}
+
+	SetCursorKey([]byte("other-key"))
+	if _, err := DecodeCursor(token); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("different key: err = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestCheckCursorSortRejectsMismatch(t *testing.T) {
+	issued := bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: 1}}
+	c := Cursor{Sort: SortID(issued), Values: []any{"high"}, ID: primitive.NewObjectID()}
+
+	for _, sort := range []bson.D{
+		{{Key: "priority", Value: 1}, {Key: "_id", Value: 1}},
+		{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}},
+		{{Key: "_id", Value: 1}},
+	} {
+		if err := checkCursorSort(c, sort); !errors.Is(err, ErrBadRequest) {
+			t.Errorf("sort %v: err = %v, want ErrBadRequest", sort, err)
+		}
+	}
+}
@@ -11,3 +11,12 @@ var (
 	ErrConflict      = errors.New("conflict (e.g. duplicate)")
 	ErrInvalidInput  = errors.New("invalid input")
 )
+
+// Precondition errors for conditional requests (If-Match).
+var (
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required: send If-Match with the ETag from GET")
+)
+
+// ErrUnsupportedMediaType is returned for request bodies in a format the endpoint does not take.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AnyVersion is the version a conditional update accepts when the client sent If-Match: *.
+const AnyVersion int64 = -1
+
+// ETag formats a document version as a strong entity tag.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// SetETag sets the ETag response header for a document version.
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// IfMatch returns the version named by the request's If-Match header: AnyVersion for "*",
+// ErrPreconditionRequired when the header is missing and ErrBadRequest when it is not one ETag
+// written by ETag (a W/ prefix is tolerated).
+func IfMatch(r *http.Request) (int64, error) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" {
+		return 0, ErrPreconditionRequired
+	}
+	if v == "*" {
+		return AnyVersion, nil
+	}
+	v = strings.TrimPrefix(v, "W/")
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return 0, fmt.Errorf("%w: If-Match must be an ETag such as \"3\"", ErrBadRequest)
+	}
+	n, err := strconv.ParseInt(v[1:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: If-Match must be an ETag such as \"3\"", ErrBadRequest)
+	}
+	return n, nil
+}
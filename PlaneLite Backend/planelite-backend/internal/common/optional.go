@@ -0,0 +1,53 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// Optional is a request field that tells an absent member (Set is false) from an explicit null
+// (Set and Null) and a value, as JSON Merge Patch (RFC 7386) needs.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		var zero T
+		o.Null, o.Value = true, zero
+		return nil
+	}
+	o.Null = false
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Ptr returns the value, or nil when the member was absent or null.
+func (o Optional[T]) Ptr() *T {
+	if !o.Set || o.Null {
+		return nil
+	}
+	v := o.Value
+	return &v
+}
+
+// MergePatchContentType is the media type of JSON Merge Patch bodies.
+const MergePatchContentType = "application/merge-patch+json"
+
+// CheckMergePatch accepts a PATCH body sent as application/merge-patch+json or plain JSON (also assumed
+// when Content-Type is missing) and returns ErrUnsupportedMediaType otherwise.
+func CheckMergePatch(r *http.Request) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil || (mt != MergePatchContentType && mt != "application/json") {
+		return fmt.Errorf("%w: send %s", ErrUnsupportedMediaType, MergePatchContentType)
+	}
+	return nil
+}
@@ -1,5 +1,15 @@
 package common
 
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
 // PageParams holds list request pagination.
 type PageParams struct {
 	Page     int
@@ -37,3 +47,115 @@ type PageResult struct {
 	PageSize   int   `json:"page_size"`
 	TotalCount int64 `json:"total_count"`
 }
+
+// ListParams selects one page of a list. In offset mode Page and PageSize pick the page; in cursor
+// mode the page starts after After (or at the beginning when After is nil), which stays correct
+// while items are inserted.
+type ListParams struct {
+	PageParams
+	CursorMode bool
+	After      *Cursor
+	// SkipCount leaves out the total count, which costs a full count query.
+	SkipCount bool
+}
+
+// WantsPage reports whether the query asks for paging (cursor, limit, page or page_size). Lists that
+// returned a plain array before pagination existed keep doing so unless it does.
+func WantsPage(r *http.Request) bool {
+	q := r.URL.Query()
+	return q.Has("cursor") || q.Has("limit") || q.Has("page") || q.Has("page_size")
+}
+
+// ParseListParams reads page, page_size (or limit), cursor and count from the query. Passing cursor,
+// even empty for the first page, selects cursor mode; count=false skips the total count.
+func ParseListParams(r *http.Request) (ListParams, error) {
+	q := r.URL.Query()
+	var p ListParams
+	p.Page, _ = strconv.Atoi(q.Get("page"))
+	p.PageSize, _ = strconv.Atoi(q.Get("page_size"))
+	if p.PageSize == 0 {
+		p.PageSize, _ = strconv.Atoi(q.Get("limit"))
+	}
+	p.Normalize()
+	if q.Has("cursor") {
+		p.CursorMode = true
+		if v := q.Get("cursor"); v != "" {
+			c, err := DecodeCursor(v)
+			if err != nil {
+				return p, err
+			}
+			p.After = &c
+		}
+	}
+	p.SkipCount = q.Get("count") == "false"
+	return p, nil
+}
+
+// ListPage is one page of a list response. NextCursor is empty on the last page; Page is only set in
+// offset mode and TotalCount only when counted.
+type ListPage[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	TotalCount *int64 `json:"total_count,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FindPage returns the page of col's documents matching filter selected by p, in sort order.
+// sort must end with _id. Stages in extra run right after the filter, e.g. to add computed
+// fields that sort refers to.
+func FindPage[T any](ctx context.Context, col *mongo.Collection, filter bson.M, sort bson.D, p ListParams, extra ...bson.D) (*ListPage[*T], error) {
+	page := &ListPage[*T]{Items: []*T{}, PageSize: p.PageSize}
+	if !p.SkipCount {
+		total, err := col.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalCount = &total
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	pipeline = append(pipeline, extra...)
+	if p.After != nil {
+		if err := checkCursorSort(*p.After, sort); err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: KeysetFilter(sort, *p.After)}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	if !p.CursorMode {
+		page.Page = p.Page
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: int64(p.Offset())}})
+	}
+	// Fetch one extra to know whether another page exists.
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: int64(p.PageSize) + 1}})
+	cur, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var docs []bson.M
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	more := len(docs) > p.PageSize
+	if more {
+		docs = docs[:p.PageSize]
+	}
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		item := new(T)
+		if err := bson.Unmarshal(raw, item); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if more {
+		last := docs[len(docs)-1]
+		id, _ := last["_id"].(primitive.ObjectID)
+		page.NextCursor = EncodeCursor(Cursor{Sort: SortID(sort), Values: SortValues(last, sort), ID: id})
+	}
+	return page, nil
+}
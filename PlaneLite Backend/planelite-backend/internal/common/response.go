@@ -20,9 +20,11 @@ type Success struct {
 	Data any `json:"data,omitempty"`
 }
 
-// ErrorResp is the standard error envelope.
+// ErrorResp is the standard error envelope. Data optionally carries the current state of the resource,
+// e.g. on 412 Precondition Failed.
 type ErrorResp struct {
 	Error string `json:"error"`
+	Data  any    `json:"data,omitempty"`
 }
 
 // OK writes 200 with data.
@@ -63,6 +65,12 @@ func StatusFromError(err error) int {
 		return http.StatusUnauthorized
 	case errors.Is(err, ErrForbidden):
 		return http.StatusForbidden
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
 	case errors.Is(err, ErrConflict), errors.Is(err, ErrInvalidInput):
 		return http.StatusBadRequest
 	default:
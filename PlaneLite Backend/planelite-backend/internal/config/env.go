@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -12,6 +13,24 @@ type Config struct {
 	DBName         string
 	JWTSecret      string
 	JWTExpiryHours int
+	// CursorSecret signs pagination cursors; defaults to JWTSecret.
+	CursorSecret string
+	// TaskParentCompletion is "block" (default) or "cascade": what completing a task with open sub-tasks does.
+	TaskParentCompletion string
+
+	// StorageBackend is "local" (default) or "s3"; it selects where attachment blobs are kept.
+	StorageBackend  string
+	StorageLocalDir string
+	S3Endpoint      string // e.g. https://s3.us-east-1.amazonaws.com or http://localhost:9000
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	// AttachmentMaxBytes caps a single upload; AttachmentTypes lists allowed MIME types ("image/*" matches any image).
+	AttachmentMaxBytes int64
+	AttachmentTypes    []string
+	// TrashRetentionDays is how long deleted tasks stay in the trash before being purged.
+	TrashRetentionDays int
 }
 
 // LoadEnv loads config from environment. Call Validate() after load.
@@ -20,12 +39,35 @@ func LoadEnv() *Config {
 	if hours <= 0 {
 		hours = 24
 	}
+	maxMB, _ := strconv.Atoi(getEnv("ATTACHMENT_MAX_MB", "10"))
+	if maxMB <= 0 {
+		maxMB = 10
+	}
+	retention, _ := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if retention <= 0 {
+		retention = 30
+	}
 	return &Config{
 		Port:           getEnv("PORT", "8080"),
 		MongoURI:       getEnv("MONGO_URI", ""),
 		DBName:         getEnv("DB_NAME", "planelite"),
 		JWTSecret:      getEnv("JWT_SECRET", ""),
 		JWTExpiryHours: hours,
+		CursorSecret:   getEnv("CURSOR_SECRET", os.Getenv("JWT_SECRET")),
+
+		TaskParentCompletion: getEnv("TASK_PARENT_COMPLETION", "block"),
+
+		StorageBackend:  getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalDir: getEnv("STORAGE_LOCAL_DIR", "./data/attachments"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", ""),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+
+		AttachmentMaxBytes: int64(maxMB) << 20,
+		AttachmentTypes:    splitList(getEnv("ATTACHMENT_TYPES", "image/*,text/plain,application/pdf,application/json,application/zip")),
+		TrashRetentionDays: retention,
 	}
 }
 
@@ -37,6 +79,18 @@ func (c *Config) Validate() error {
 	if c.JWTSecret == "" {
 		return fmt.Errorf("config: JWT_SECRET is required")
 	}
+	if c.TaskParentCompletion != "block" && c.TaskParentCompletion != "cascade" {
+		return fmt.Errorf("config: TASK_PARENT_COMPLETION must be block or cascade")
+	}
+	switch c.StorageBackend {
+	case "local":
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" || c.S3AccessKey == "" || c.S3SecretKey == "" {
+			return fmt.Errorf("config: S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 backend")
+		}
+	default:
+		return fmt.Errorf("config: STORAGE_BACKEND must be local or s3")
+	}
 	return nil
 }
 
@@ -46,3 +100,14 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// splitList parses a comma-separated value, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -3,12 +3,44 @@ package config
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// EnsureIndexes creates required indexes: users.email unique, memberships (user_id+workspace_id) unique.
+// EnsureRequiredIndexes creates the unique indexes features rely on for correctness. The server must
+// not start without them.
+func EnsureRequiredIndexes(ctx context.Context, db *mongo.Database) error {
+	// tasks: one key per (project, sequence). Partial so tasks created before keys existed do not
+	// collide until backfilled.
+	_, err := db.Collection("tasks").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "sequence", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"sequence": bson.M{"$gt": 0}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// task_redirects: an old key of a moved task resolves to exactly one task.
+	_, err = db.Collection("task_redirects").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "sequence", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// timers: at most one running timer per user.
+	_, err = db.Collection("timers").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// EnsureIndexes creates the other indexes, which speed up queries or keep data tidy.
 func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	// users: one account per email.
 	users := db.Collection("users")
 	_, err := users.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys:    map[string]int{"email": 1},
@@ -18,10 +50,288 @@ func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	// memberships: one per user and workspace.
 	memberships := db.Collection("memberships")
 	_, err = memberships.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys:    map[string]int{"user_id": 1, "workspace_id": 1},
 		Options: options.Index().SetUnique(true),
 	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: "my tasks" lookups.
+	tasks := db.Collection("tasks")
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]int{"assignee_ids": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	// states: keys are unique within a project.
+	states := db.Collection("states")
+	_, err = states.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// transitions: one rule per (from, to) in a project.
+	transitions := db.Collection("transitions")
+	_, err = transitions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "project_id", Value: 1}, {Key: "from", Value: 1}, {Key: "to", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// labels: names are unique per workspace or project.
+	labels := db.Collection("labels")
+	_, err = labels.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "workspace_id", Value: 1}, {Key: "project_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: label filters.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "label_ids", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: overdue and due-soon queries.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "due_date", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: sub-task lookups.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]int{"parent_id": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	// task_relations: a relation is stored once per direction.
+	relations := db.Collection("task_relations")
+	_, err = relations.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "related_id", Value: 1}, {Key: "type", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// comments: threads, paged by _id.
+	comments := db.Collection("comments")
+	_, err = comments.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// mentions: the mention inbox, newest first.
+	mentions := db.Collection("mentions")
+	_, err = mentions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// mentions: syncing edits of a description or comment.
+	_, err = mentions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "comment_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// attachments: per-task listing.
+	attachments := db.Collection("attachments")
+	_, err = attachments.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: map[string]int{"task_id": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	// projects: identifiers are unique in a workspace. Partial so projects created before identifiers
+	// existed do not collide until backfilled.
+	projects := db.Collection("projects")
+	_, err = projects.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "identifier", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"identifier": bson.M{"$type": "string", "$gt": ""}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// projects: resolving old task keys.
+	_, err = projects.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "previous_identifiers", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: the trash purge.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    map[string]int{"deleted_at": 1},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: filtered and sorted listings.
+	for _, field := range []string{"status", "priority", "created_by", "created_at", "updated_at"} {
+		_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "project_id", Value: 1}, {Key: field, Value: 1}, {Key: "_id", Value: 1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// activities: the activity feed, newest first.
+	_, err = db.Collection("activities").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks, projects and comments: search. A collection has at most one text index; titles and names
+	// weigh more than bodies when ranking.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "key", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().SetName("search_text").SetWeights(bson.M{"title": 5, "key": 5, "description": 1}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("projects").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "identifier", Value: "text"}},
+		Options: options.Index().SetName("search_text").SetWeights(bson.M{"name": 5, "identifier": 5}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Collection("comments").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "body", Value: "text"}},
+		Options: options.Index().SetName("search_text"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// worklogs: per-task listing, newest first.
+	worklogs := db.Collection("worklogs")
+	_, err = worklogs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "date", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// worklogs: timesheets.
+	_, err = worklogs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "date", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// cycles: per-project listing, latest first.
+	_, err = db.Collection("cycles").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "start_date", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// cycle_scope_events: a cycle's scope history, and a task's latest event.
+	scopeEvents := db.Collection("cycle_scope_events")
+	_, err = scopeEvents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "cycle_id", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = scopeEvents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: cycle members.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "cycle_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// task_status_changes: burndowns.
+	_, err = db.Collection("task_status_changes").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// modules: per-project listing by name.
+	_, err = db.Collection("modules").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// tasks: module members.
+	_, err = tasks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "module_ids", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// milestones: the roadmap, and unlinking deleted tasks and modules.
+	milestones := db.Collection("milestones")
+	_, err = milestones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "target_date", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = milestones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "task_ids", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = milestones.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "module_ids", Value: 1}},
+	})
 	return err
 }
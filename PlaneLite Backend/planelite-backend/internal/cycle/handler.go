@@ -0,0 +1,363 @@
+package cycle
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST /workspaces/:id/projects/:pid/cycles. Dates are RFC 3339.
+type CreateRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+}
+
+// UpdateRequest is the JSON body for PATCH .../cycles/:cid. Absent fields are left unchanged.
+type UpdateRequest struct {
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+}
+
+// AddTasksRequest is the JSON body for POST .../cycles/:cid/tasks.
+type AddTasksRequest struct {
+	TaskIDs []string `json:"task_ids"`
+}
+
+// CloseRequest is the optional JSON body for POST .../cycles/:cid/close.
+type CloseRequest struct {
+	CarryOverTo string `json:"carry_over_to"` // open cycle for the unfinished tasks; empty drops them from any cycle
+}
+
+// Create handles POST /workspaces/:id/projects/:pid/cycles.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, ok := projectPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	c, err := h.svc.Create(r.Context(), wsID, pid, userID, Input{
+		Name:        req.Name,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, c)
+}
+
+// List handles GET /workspaces/:id/projects/:pid/cycles (latest first, paged; see common.ParseListParams).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, ok := projectPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.List(r.Context(), wsID, pid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// GetByID handles GET /workspaces/:id/projects/:pid/cycles/:cid, with status, progress and scope changes.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	d, err := h.svc.Get(r.Context(), wsID, pid, cid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// ListScope handles GET /workspaces/:id/projects/:pid/cycles/:cid/scope (newest first, paged).
+func (h *Handler) ListScope(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.ListScope(r.Context(), wsID, pid, cid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// Burndown handles GET /workspaces/:id/projects/:pid/cycles/:cid/burndown: per day of the cycle the
+// scope, completed and remaining tasks and estimates, and the ideal line.
+func (h *Handler) Burndown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	b, err := h.svc.Burndown(r.Context(), wsID, pid, cid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, b)
+}
+
+// Update handles PATCH /workspaces/:id/projects/:pid/cycles/:cid.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	c, err := h.svc.Update(r.Context(), wsID, pid, cid, Patch{
+		Name:        req.Name,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, c)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/cycles/:cid.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, pid, cid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// AddTasks handles POST /workspaces/:id/projects/:pid/cycles/:cid/tasks.
+func (h *Handler) AddTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req AddTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(req.TaskIDs))
+	for _, v := range req.TaskIDs {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	d, err := h.svc.AddTasks(r.Context(), wsID, pid, cid, userID, ids)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// RemoveTask handles DELETE /workspaces/:id/projects/:pid/cycles/:cid/tasks/:tid.
+func (h *Handler) RemoveTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.RemoveTask(r.Context(), wsID, pid, cid, userID, tid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// Close handles POST /workspaces/:id/projects/:pid/cycles/:cid/close.
+func (h *Handler) Close(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, cid, ok := cyclePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CloseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+	}
+	var carryTo *primitive.ObjectID
+	if req.CarryOverTo != "" {
+		id, err := primitive.ObjectIDFromHex(req.CarryOverTo)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		carryTo = &id
+	}
+	d, err := h.svc.Close(r.Context(), wsID, pid, cid, userID, carryTo)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// manager returns the caller's ID if they may manage cycles, replying 401 or 403 otherwise.
+func manager(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	if !CanManageCycle(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// projectPath parses the workspace and project IDs from the route.
+func projectPath(r *http.Request) (wsID, pid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, false
+	}
+	return wsID, pid, true
+}
+
+// cyclePath parses the workspace, project and cycle IDs from the route.
+func cyclePath(r *http.Request) (wsID, pid, cid primitive.ObjectID, ok bool) {
+	if wsID, pid, ok = projectPath(r); !ok {
+		return wsID, pid, cid, false
+	}
+	var err error
+	if cid, err = primitive.ObjectIDFromHex(r.PathValue("cid")); err != nil {
+		return wsID, pid, cid, false
+	}
+	return wsID, pid, cid, true
+}
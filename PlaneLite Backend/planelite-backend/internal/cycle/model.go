@@ -0,0 +1,174 @@
+package cycle
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is where a cycle is in its life; derived from its dates and whether it was closed.
+type Status string
+
+const (
+	StatusUpcoming  Status = "upcoming"  // starts in the future
+	StatusActive    Status = "active"    // between start and end
+	StatusEnded     Status = "ended"     // past its end date but not closed yet
+	StatusCompleted Status = "completed" // closed; no more scope changes
+)
+
+// Cycle is a time-boxed iteration (sprint) of a project. Tasks join a cycle through task.Task.CycleID;
+// a task is in at most one cycle. Open cycles of a project never overlap.
+type Cycle struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	StartDate   time.Time          `bson:"start_date" json:"start_date"`
+	EndDate     time.Time          `bson:"end_date" json:"end_date"`
+
+	// ClosedAt is set once the cycle is closed; unfinished tasks then moved to CarriedOverTo, if set.
+	ClosedAt      *time.Time          `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+	ClosedBy      *primitive.ObjectID `bson:"closed_by,omitempty" json:"closed_by,omitempty"`
+	CarriedOverTo *primitive.ObjectID `bson:"carried_over_to,omitempty" json:"carried_over_to,omitempty"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Status returns the cycle's status at now.
+func (c *Cycle) Status(now time.Time) Status {
+	switch {
+	case c.ClosedAt != nil:
+		return StatusCompleted
+	case now.Before(c.StartDate):
+		return StatusUpcoming
+	case now.After(c.EndDate):
+		return StatusEnded
+	}
+	return StatusActive
+}
+
+// EventKind is the kind of a scope change.
+type EventKind string
+
+const (
+	EventAdded   EventKind = "added"
+	EventRemoved EventKind = "removed"
+	// EventCarriedIn and EventCarriedOut record unfinished tasks moving on when a cycle is closed.
+	EventCarriedIn  EventKind = "carried_in"
+	EventCarriedOut EventKind = "carried_out"
+)
+
+// ScopeEvent records a task joining or leaving a cycle. MidCycle is set for changes after the cycle
+// started; Estimate is the task's estimate at the time.
+type ScopeEvent struct {
+	ID       primitive.ObjectID  `bson:"_id,omitempty" json:"_id"`
+	CycleID  primitive.ObjectID  `bson:"cycle_id" json:"cycle_id"`
+	TaskID   primitive.ObjectID  `bson:"task_id" json:"task_id"`
+	Kind     EventKind           `bson:"kind" json:"kind"`
+	Estimate *float64            `bson:"estimate,omitempty" json:"estimate,omitempty"`
+	MidCycle bool                `bson:"mid_cycle" json:"mid_cycle"`
+	UserID   primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Other    *primitive.ObjectID `bson:"other_cycle_id,omitempty" json:"other_cycle_id,omitempty"` // the cycle carried to or from
+	At       time.Time           `bson:"at" json:"at"`
+}
+
+// TaskInfo is what cycles need to know about a task; provided by task.Service.
+type TaskInfo struct {
+	ID       primitive.ObjectID
+	CycleID  *primitive.ObjectID
+	Estimate *float64
+	// Closed is set for tasks in a completed or cancelled state.
+	Closed bool
+}
+
+// ClosedChange is a task being closed (completed or cancelled) or reopened at some time.
+type ClosedChange struct {
+	At     time.Time
+	Closed bool
+}
+
+// StatusTimeline is when a task was closed and reopened; provided by task.Service from its status
+// history. Initially is whether the task was closed before its first change.
+type StatusTimeline struct {
+	TaskID    primitive.ObjectID
+	Estimate  *float64
+	Initially bool
+	Changes   []ClosedChange
+}
+
+// ClosedAt reports whether the task was closed at t.
+func (tl *StatusTimeline) ClosedAt(t time.Time) bool {
+	closed := tl.Initially
+	for _, c := range tl.Changes {
+		if c.At.After(t) {
+			break
+		}
+		closed = c.Closed
+	}
+	return closed
+}
+
+// Count is a number of tasks and the sum of their estimates.
+type Count struct {
+	Tasks    int     `json:"tasks"`
+	Estimate float64 `json:"estimate"`
+}
+
+func (c *Count) add(estimate *float64) {
+	c.Tasks++
+	if estimate != nil {
+		c.Estimate += *estimate
+	}
+}
+
+// Progress is the state of the tasks currently in a cycle.
+type Progress struct {
+	Total Count `json:"total"`
+	Done  Count `json:"done"`
+}
+
+// Scope sums a cycle's scope changes. AddedMidCycle and RemovedMidCycle are the part of Added and
+// Removed that happened after the cycle started.
+type Scope struct {
+	Added           Count `json:"added"`
+	Removed         Count `json:"removed"`
+	AddedMidCycle   Count `json:"added_mid_cycle"`
+	RemovedMidCycle Count `json:"removed_mid_cycle"`
+	CarriedIn       Count `json:"carried_in"`
+	CarriedOut      Count `json:"carried_out"`
+}
+
+// Ideal is the ideal remaining work on a day: the scope at the start of the cycle burned down
+// evenly to zero on its last day.
+type Ideal struct {
+	Tasks    float64 `json:"tasks"`
+	Estimate float64 `json:"estimate"`
+}
+
+// BurnDay is one day of a cycle chart. Scope and Completed give the burnup lines, Remaining the
+// burndown; all three are nil for days that have not happened yet.
+type BurnDay struct {
+	Date      time.Time `json:"date"`
+	Scope     *Count    `json:"scope"`
+	Completed *Count    `json:"completed"`
+	Remaining *Count    `json:"remaining"`
+	Ideal     Ideal     `json:"ideal"`
+}
+
+// Burndown is the day-by-day chart data of a cycle, from its start day to its end day (UTC).
+type Burndown struct {
+	CycleID primitive.ObjectID `json:"cycle_id"`
+	Days    []BurnDay          `json:"days"`
+}
+
+// Detail is a cycle as returned by Get, with its status, progress and scope changes.
+type Detail struct {
+	*Cycle
+	Status   Status   `json:"status"`
+	Progress Progress `json:"progress"`
+	Scope    Scope    `json:"scope"`
+}
@@ -0,0 +1,11 @@
+package cycle
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageCycle: admin and PROJECT_MANAGER can create, change, close and delete cycles and move tasks
+// in and out of them.
+func CanManageCycle(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
@@ -0,0 +1,138 @@
+package cycle
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"planelite-backend/internal/common"
+)
+
+type Repository struct {
+	col    *mongo.Collection
+	events *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("cycles"), events: db.Collection("cycle_scope_events")}
+}
+
+func (r *Repository) Create(ctx context.Context, c *Cycle) error {
+	result, err := r.col.InsertOne(ctx, c)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		c.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Cycle, error) {
+	var c Cycle
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListPage returns one page of the project's cycles, latest start first.
+func (r *Repository) ListPage(ctx context.Context, projectID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Cycle], error) {
+	sort := bson.D{{Key: "start_date", Value: -1}, {Key: "_id", Value: -1}}
+	return common.FindPage[Cycle](ctx, r.col, bson.M{"project_id": projectID}, sort, p)
+}
+
+// FindOverlapping returns the open cycles of the project, other than exclude, whose dates overlap
+// start to end.
+func (r *Repository) FindOverlapping(ctx context.Context, projectID, exclude primitive.ObjectID, start, end time.Time) ([]*Cycle, error) {
+	cur, err := r.col.Find(ctx, bson.M{
+		"project_id": projectID,
+		"_id":        bson.M{"$ne": exclude},
+		"closed_at":  nil,
+		"start_date": bson.M{"$lte": end},
+		"end_date":   bson.M{"$gte": start},
+	}, options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Cycle
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+// Close marks an open cycle closed. It returns common.ErrConflict if the cycle was closed meanwhile.
+func (r *Repository) Close(ctx context.Context, id, userID primitive.ObjectID, at time.Time, carriedTo *primitive.ObjectID) error {
+	set := bson.M{"closed_at": at, "closed_by": userID, "updated_at": at}
+	if carriedTo != nil {
+		set["carried_over_to"] = *carriedTo
+	}
+	res, err := r.col.UpdateOne(ctx, bson.M{"_id": id, "closed_at": nil}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return common.ErrConflict
+	}
+	return nil
+}
+
+// Delete removes a cycle and its scope history.
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := r.events.DeleteMany(ctx, bson.M{"cycle_id": id}); err != nil {
+		return err
+	}
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *Repository) AddEvents(ctx context.Context, events []ScopeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]any, len(events))
+	for i := range events {
+		docs[i] = events[i]
+	}
+	_, err := r.events.InsertMany(ctx, docs)
+	return err
+}
+
+// ListEvents returns every scope event of the cycle in order.
+func (r *Repository) ListEvents(ctx context.Context, cycleID primitive.ObjectID) ([]*ScopeEvent, error) {
+	cur, err := r.events.Find(ctx, bson.M{"cycle_id": cycleID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*ScopeEvent
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventsPage returns one page of the cycle's scope events, newest first.
+func (r *Repository) EventsPage(ctx context.Context, cycleID primitive.ObjectID, p common.ListParams) (*common.ListPage[*ScopeEvent], error) {
+	return common.FindPage[ScopeEvent](ctx, r.events, bson.M{"cycle_id": cycleID}, bson.D{{Key: "_id", Value: -1}}, p)
+}
+
+// LastEvent returns the most recent scope event of a task, in any cycle.
+func (r *Repository) LastEvent(ctx context.Context, taskID primitive.ObjectID) (*ScopeEvent, error) {
+	var e ScopeEvent
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if err := r.events.FindOne(ctx, bson.M{"task_id": taskID}, opts).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
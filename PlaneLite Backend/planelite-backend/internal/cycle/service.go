@@ -0,0 +1,548 @@
+package cycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+)
+
+// MaxTasksPerRequest bounds the task IDs of one AddTasks call.
+const MaxTasksPerRequest = 100
+
+// Tasks is what cycles need from tasks. Implemented by task.Service.
+type Tasks interface {
+	// CycleTask returns a live task of the project.
+	CycleTask(ctx context.Context, projectID, id primitive.ObjectID) (*TaskInfo, error)
+	// CycleTasks returns the live, unarchived tasks of a cycle.
+	CycleTasks(ctx context.Context, cycleID primitive.ObjectID) ([]TaskInfo, error)
+	// SetCycle puts tasks into a cycle, or takes them out of any with a nil cycleID.
+	SetCycle(ctx context.Context, ids []primitive.ObjectID, cycleID *primitive.ObjectID) error
+	// ClearCycle takes every task out of a deleted cycle.
+	ClearCycle(ctx context.Context, cycleID primitive.ObjectID) error
+	// StatusTimelines returns when each task was closed and reopened, judging tasks that have since
+	// been purged by the states of projectID.
+	StatusTimelines(ctx context.Context, projectID primitive.ObjectID, ids []primitive.ObjectID) ([]StatusTimeline, error)
+}
+
+type Service struct {
+	repo     *Repository
+	tasks    Tasks
+	projects *project.Service
+}
+
+func NewService(repo *Repository, tasks Tasks, projects *project.Service) *Service {
+	return &Service{repo: repo, tasks: tasks, projects: projects}
+}
+
+// Input holds a new cycle.
+type Input struct {
+	Name        string
+	Description string
+	StartDate   time.Time
+	EndDate     time.Time
+}
+
+// Patch lists the changes to a cycle; nil fields are left alone.
+type Patch struct {
+	Name        *string
+	Description *string
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+// Create adds a cycle to the project. Its dates must not overlap another open cycle of the project.
+func (s *Service) Create(ctx context.Context, workspaceID, projectID, userID primitive.ObjectID, in Input) (*Cycle, error) {
+	if err := s.checkProject(ctx, workspaceID, projectID); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+	if err := s.checkDates(ctx, projectID, primitive.NilObjectID, in.StartDate, in.EndDate); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	c := &Cycle{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		Name:        name,
+		Description: in.Description,
+		StartDate:   in.StartDate,
+		EndDate:     in.EndDate,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns a cycle of the project with its status, progress and scope changes.
+func (s *Service) Get(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Detail, error) {
+	c, err := s.find(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	d := &Detail{Cycle: c, Status: c.Status(time.Now())}
+	tasks, err := s.tasks.CycleTasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		d.Progress.Total.add(t.Estimate)
+		if t.Closed {
+			d.Progress.Done.add(t.Estimate)
+		}
+	}
+	events, err := s.repo.ListEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		switch e.Kind {
+		case EventAdded:
+			d.Scope.Added.add(e.Estimate)
+			if e.MidCycle {
+				d.Scope.AddedMidCycle.add(e.Estimate)
+			}
+		case EventRemoved:
+			d.Scope.Removed.add(e.Estimate)
+			if e.MidCycle {
+				d.Scope.RemovedMidCycle.add(e.Estimate)
+			}
+		case EventCarriedIn:
+			d.Scope.CarriedIn.add(e.Estimate)
+		case EventCarriedOut:
+			d.Scope.CarriedOut.add(e.Estimate)
+		}
+	}
+	return d, nil
+}
+
+// List returns one page of the project's cycles, latest start first.
+func (s *Service) List(ctx context.Context, workspaceID, projectID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Cycle], error) {
+	if err := s.checkProject(ctx, workspaceID, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPage(ctx, projectID, p)
+}
+
+// ListScope returns one page of the cycle's scope events, newest first.
+func (s *Service) ListScope(ctx context.Context, workspaceID, projectID, id primitive.ObjectID, p common.ListParams) (*common.ListPage[*ScopeEvent], error) {
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.EventsPage(ctx, id, p)
+}
+
+// Update changes an open cycle.
+func (s *Service) Update(ctx context.Context, workspaceID, projectID, id primitive.ObjectID, p Patch) (*Cycle, error) {
+	c, err := s.findOpen(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{"updated_at": time.Now()}
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", common.ErrInvalidInput)
+		}
+		set["name"] = name
+	}
+	if p.Description != nil {
+		set["description"] = *p.Description
+	}
+	if p.StartDate != nil || p.EndDate != nil {
+		start, end := c.StartDate, c.EndDate
+		if p.StartDate != nil {
+			start = *p.StartDate
+		}
+		if p.EndDate != nil {
+			end = *p.EndDate
+		}
+		if err := s.checkDates(ctx, projectID, id, start, end); err != nil {
+			return nil, err
+		}
+		set["start_date"], set["end_date"] = start, end
+	}
+	if err := s.repo.Update(ctx, id, set); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete removes a cycle and its scope history; its tasks stay, outside any cycle.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) error {
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	if err := s.tasks.ClearCycle(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// AddTasks puts tasks of the project into an open cycle. A task in another cycle leaves it (recorded
+// there as removed unless that cycle is completed); tasks already in the cycle are skipped.
+func (s *Service) AddTasks(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID, taskIDs []primitive.ObjectID) (*Detail, error) {
+	if len(taskIDs) == 0 || len(taskIDs) > MaxTasksPerRequest {
+		return nil, fmt.Errorf("%w: give 1 to %d task ids", common.ErrInvalidInput, MaxTasksPerRequest)
+	}
+	c, err := s.findOpen(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var add []primitive.ObjectID
+	var events []ScopeEvent
+	others := map[primitive.ObjectID]*Cycle{}
+	seen := map[primitive.ObjectID]bool{}
+	for _, tid := range taskIDs {
+		if seen[tid] {
+			continue
+		}
+		seen[tid] = true
+		t, err := s.tasks.CycleTask(ctx, projectID, tid)
+		if err != nil {
+			return nil, err
+		}
+		if t.CycleID != nil && *t.CycleID == id {
+			continue
+		}
+		if t.CycleID != nil {
+			prev, ok := others[*t.CycleID]
+			if !ok {
+				if prev, err = s.repo.FindByID(ctx, *t.CycleID); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+					return nil, err
+				}
+				others[*t.CycleID] = prev
+			}
+			if prev != nil && prev.ClosedAt == nil {
+				events = append(events, event(prev, t, EventRemoved, userID, now))
+			}
+		}
+		add = append(add, tid)
+		events = append(events, event(c, t, EventAdded, userID, now))
+	}
+	if len(add) > 0 {
+		if err := s.tasks.SetCycle(ctx, add, &id); err != nil {
+			return nil, err
+		}
+		if err := s.repo.AddEvents(ctx, events); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, workspaceID, projectID, id)
+}
+
+// RemoveTask takes a task out of an open cycle.
+func (s *Service) RemoveTask(ctx context.Context, workspaceID, projectID, id, userID, taskID primitive.ObjectID) error {
+	c, err := s.findOpen(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return err
+	}
+	t, err := s.tasks.CycleTask(ctx, projectID, taskID)
+	if err != nil {
+		return err
+	}
+	if t.CycleID == nil || *t.CycleID != id {
+		return fmt.Errorf("%w: task is not in this cycle", common.ErrNotFound)
+	}
+	if err := s.tasks.SetCycle(ctx, []primitive.ObjectID{taskID}, nil); err != nil {
+		return err
+	}
+	return s.repo.AddEvents(ctx, []ScopeEvent{event(c, t, EventRemoved, userID, time.Now())})
+}
+
+// Close completes a cycle. Its unfinished tasks move to the open cycle carryTo, or out of any cycle
+// when carryTo is nil; finished tasks stay as the record of what the cycle delivered.
+func (s *Service) Close(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID, carryTo *primitive.ObjectID) (*Detail, error) {
+	c, err := s.findOpen(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	var next *Cycle
+	if carryTo != nil {
+		if *carryTo == id {
+			return nil, fmt.Errorf("%w: cannot carry tasks over to the cycle being closed", common.ErrInvalidInput)
+		}
+		if next, err = s.findOpen(ctx, workspaceID, projectID, *carryTo); err != nil {
+			return nil, fmt.Errorf("%w: carry_over_to must be an open cycle of the project", common.ErrInvalidInput)
+		}
+	}
+	tasks, err := s.tasks.CycleTasks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.repo.Close(ctx, id, userID, now, carryTo); err != nil {
+		return nil, err
+	}
+	var open []primitive.ObjectID
+	var events []ScopeEvent
+	for i := range tasks {
+		t := &tasks[i]
+		if t.Closed {
+			continue
+		}
+		open = append(open, t.ID)
+		out := event(c, t, EventCarriedOut, userID, now)
+		out.Other = carryTo
+		events = append(events, out)
+		if next != nil {
+			in := event(next, t, EventCarriedIn, userID, now)
+			in.Other = &id
+			events = append(events, in)
+		}
+	}
+	if len(open) > 0 {
+		if err := s.tasks.SetCycle(ctx, open, carryTo); err != nil {
+			return nil, err
+		}
+		if err := s.repo.AddEvents(ctx, events); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, workspaceID, projectID, id)
+}
+
+// Burndown returns the cycle's daily scope, completed and remaining work with the ideal line. Past
+// days are replayed from the scope events and the tasks' status history, so later changes do not
+// rewrite them. A task counts on a day if it was in the cycle at the end of that day (or now, for
+// today), with the estimate it had when it joined.
+func (s *Service) Burndown(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Burndown, error) {
+	c, err := s.find(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.ListEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []primitive.ObjectID
+	seen := map[primitive.ObjectID]bool{}
+	for _, e := range events {
+		if !seen[e.TaskID] {
+			seen[e.TaskID] = true
+			ids = append(ids, e.TaskID)
+		}
+	}
+	timelines, err := s.tasks.StatusTimelines(ctx, projectID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[primitive.ObjectID]*StatusTimeline, len(timelines))
+	for i := range timelines {
+		byTask[timelines[i].TaskID] = &timelines[i]
+	}
+
+	now := time.Now()
+	first, last := startOfDay(c.StartDate), startOfDay(c.EndDate)
+	out := &Burndown{CycleID: id, Days: []BurnDay{}}
+	in := map[primitive.ObjectID]*float64{} // tasks in the cycle, with their estimate
+	next := 0
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		day := BurnDay{Date: d}
+		if !d.After(now) {
+			cutoff := d.AddDate(0, 0, 1)
+			if c.ClosedAt != nil && c.ClosedAt.Before(cutoff) {
+				// Carry-over on close empties the cycle; the last day shows what it ended with.
+				cutoff = *c.ClosedAt
+			}
+			if now.Before(cutoff) {
+				cutoff = now
+			}
+			for ; next < len(events) && events[next].At.Before(cutoff); next++ {
+				e := events[next]
+				switch e.Kind {
+				case EventAdded, EventCarriedIn:
+					in[e.TaskID] = e.Estimate
+				case EventRemoved, EventCarriedOut:
+					delete(in, e.TaskID)
+				}
+			}
+			scope, completed, remaining := Count{}, Count{}, Count{}
+			for tid, estimate := range in {
+				scope.add(estimate)
+				if tl := byTask[tid]; tl != nil && tl.ClosedAt(cutoff) {
+					completed.add(estimate)
+				} else {
+					remaining.add(estimate)
+				}
+			}
+			day.Scope, day.Completed, day.Remaining = &scope, &completed, &remaining
+		}
+		out.Days = append(out.Days, day)
+	}
+
+	// The ideal line starts from the first day's scope and reaches zero on the last day.
+	if len(out.Days) > 0 && out.Days[0].Scope != nil {
+		start := out.Days[0].Scope
+		steps := float64(len(out.Days) - 1)
+		for i := range out.Days {
+			left := 1.0
+			if steps > 0 {
+				left = 1 - float64(i)/steps
+			}
+			out.Days[i].Ideal = Ideal{Tasks: float64(start.Tasks) * left, Estimate: start.Estimate * left}
+		}
+	}
+	return out, nil
+}
+
+// DeleteByTask keeps the scope history of a purged task so that past cycles still add up.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return nil
+}
+
+// MoveTask records that a task moved to another project left its cycle; task.Service drops the
+// task's cycle itself.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	last, err := s.repo.LastEvent(ctx, taskID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if last.Kind != EventAdded && last.Kind != EventCarriedIn {
+		return nil
+	}
+	c, err := s.repo.FindByID(ctx, last.CycleID)
+	if err != nil || c.ClosedAt != nil || c.ProjectID == projectID {
+		return nil
+	}
+	now := time.Now()
+	return s.repo.AddEvents(ctx, []ScopeEvent{{
+		CycleID:  c.ID,
+		TaskID:   taskID,
+		Kind:     EventRemoved,
+		Estimate: last.Estimate,
+		MidCycle: !now.Before(c.StartDate),
+		UserID:   last.UserID,
+		At:       now,
+	}})
+}
+
+// TaskHidden records that a trashed or archived task left the scope of its open cycle. Implements
+// task.ScopeTracker.
+func (s *Service) TaskHidden(ctx context.Context, t TaskInfo, userID primitive.ObjectID) error {
+	c, last, err := s.openScope(ctx, t)
+	if err != nil || c == nil || last == nil || (last.Kind != EventAdded && last.Kind != EventCarriedIn) {
+		return err
+	}
+	return s.repo.AddEvents(ctx, []ScopeEvent{event(c, &t, EventRemoved, userID, time.Now())})
+}
+
+// TaskShown records that a restored or unarchived task is back in the scope of its open cycle. Only
+// tasks that TaskHidden took out are put back: a task still pointing at the cycle whose last event
+// there is a removal was hidden, since taking a task out of a cycle clears its cycle.
+// Implements task.ScopeTracker.
+func (s *Service) TaskShown(ctx context.Context, t TaskInfo, userID primitive.ObjectID) error {
+	c, last, err := s.openScope(ctx, t)
+	if err != nil || c == nil || last == nil || last.Kind != EventRemoved {
+		return err
+	}
+	return s.repo.AddEvents(ctx, []ScopeEvent{event(c, &t, EventAdded, userID, time.Now())})
+}
+
+// openScope returns the task's cycle unless it is closed, with the task's last scope event in that
+// cycle (nil if its last event is elsewhere or it has none).
+func (s *Service) openScope(ctx context.Context, t TaskInfo) (*Cycle, *ScopeEvent, error) {
+	if t.CycleID == nil {
+		return nil, nil, nil
+	}
+	c, err := s.repo.FindByID(ctx, *t.CycleID)
+	if err != nil || c.ClosedAt != nil {
+		return nil, nil, nil
+	}
+	last, err := s.repo.LastEvent(ctx, t.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return c, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if last.CycleID != c.ID {
+		return c, nil, nil
+	}
+	return c, last, nil
+}
+
+// checkProject verifies that the project is in the workspace.
+func (s *Service) checkProject(ctx context.Context, workspaceID, projectID primitive.ObjectID) error {
+	p, err := s.projects.GetByID(ctx, projectID)
+	if err != nil || p.WorkspaceID != workspaceID {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// checkDates validates a cycle's dates and that no other open cycle of the project overlaps them.
+func (s *Service) checkDates(ctx context.Context, projectID, id primitive.ObjectID, start, end time.Time) error {
+	if start.IsZero() || end.IsZero() {
+		return fmt.Errorf("%w: start_date and end_date are required", common.ErrInvalidInput)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%w: end_date must be after start_date", common.ErrInvalidInput)
+	}
+	overlapping, err := s.repo.FindOverlapping(ctx, projectID, id, start, end)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return fmt.Errorf("%w: dates overlap cycle %q", common.ErrConflict, overlapping[0].Name)
+	}
+	return nil
+}
+
+// find returns a cycle of the project in the workspace.
+func (s *Service) find(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Cycle, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil || c.WorkspaceID != workspaceID || c.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	return c, nil
+}
+
+// findOpen returns a cycle of the project that is not closed yet.
+func (s *Service) findOpen(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Cycle, error) {
+	c, err := s.find(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ClosedAt != nil {
+		return nil, fmt.Errorf("%w: cycle is completed", common.ErrConflict)
+	}
+	return c, nil
+}
+
+// startOfDay truncates t to midnight UTC of its day; charts are drawn per UTC day.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// event builds a scope event of kind for task t in cycle c.
+func event(c *Cycle, t *TaskInfo, kind EventKind, userID primitive.ObjectID, at time.Time) ScopeEvent {
+	return ScopeEvent{
+		CycleID:  c.ID,
+		TaskID:   t.ID,
+		Kind:     kind,
+		Estimate: t.Estimate,
+		MidCycle: !at.Before(c.StartDate),
+		UserID:   userID,
+		At:       at,
+	}
+}
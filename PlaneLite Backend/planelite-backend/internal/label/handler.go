@@ -0,0 +1,183 @@
+package label
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST /workspaces/:id/labels.
+type CreateRequest struct {
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	ProjectID string `json:"project_id"` // optional; empty creates a workspace-wide label
+	ParentID  string `json:"parent_id"`  // optional; groups the label under a parent
+}
+
+// UpdateRequest is the JSON body for PATCH /workspaces/:id/labels/:lid.
+// parent_id "" moves the label out of its group.
+type UpdateRequest struct {
+	Name     *string `json:"name"`
+	Color    *string `json:"color"`
+	ParentID *string `json:"parent_id"`
+}
+
+// Create handles POST /workspaces/:id/labels.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageLabels(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	projectID, err := optionalID(req.ProjectID)
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	parentID, err := optionalID(req.ParentID)
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	l, err := h.svc.Create(r.Context(), wsID, projectID, parentID, req.Name, req.Color)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, l)
+}
+
+// List handles GET /workspaces/:id/labels[?project_id=].
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	projectID, err := optionalID(r.URL.Query().Get("project_id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	list, err := h.svc.List(r.Context(), wsID, projectID)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	if list == nil {
+		list = []*Label{}
+	}
+	common.OK(w, list)
+}
+
+// Update handles PATCH /workspaces/:id/labels/:lid.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageLabels(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	lid, err := primitive.ObjectIDFromHex(r.PathValue("lid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p := Patch{Name: req.Name, Color: req.Color}
+	if req.ParentID != nil {
+		parentID, err := optionalID(*req.ParentID)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		p.ParentID = parentID
+		p.ClearParent = parentID == nil
+	}
+	l, err := h.svc.Update(r.Context(), wsID, lid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, l)
+}
+
+// Delete handles DELETE /workspaces/:id/labels/:lid. The label is detached from all tasks.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageLabels(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	lid, err := primitive.ObjectIDFromHex(r.PathValue("lid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, lid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// optionalID parses hex into an ObjectID; empty input yields nil.
+func optionalID(hex string) (*primitive.ObjectID, error) {
+	if hex == "" {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,27 @@
+package label
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Label tags tasks. Labels without a ProjectID are shared by every project in the workspace.
+// A label with a ParentID belongs to that parent's group (one level deep).
+type Label struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID  `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   *primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitempty"`
+	ParentID    *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+
+	Name  string `bson:"name" json:"name"`
+	Color string `bson:"color" json:"color"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// AppliesTo reports whether the label can be attached to tasks of projectID.
+func (l *Label) AppliesTo(projectID primitive.ObjectID) bool {
+	return l.ProjectID == nil || *l.ProjectID == projectID
+}
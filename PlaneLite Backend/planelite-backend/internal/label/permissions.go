@@ -0,0 +1,10 @@
+package label
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageLabels: only PROJECT_MANAGER (and ADMIN) can create, edit or delete labels.
+func CanManageLabels(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
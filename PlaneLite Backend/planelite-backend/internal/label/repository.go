@@ -0,0 +1,117 @@
+package label
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("labels")}
+}
+
+func (r *Repository) Create(ctx context.Context, l *Label) error {
+	doc := bson.M{
+		"workspace_id": l.WorkspaceID,
+		"name":         l.Name,
+		"color":        l.Color,
+		"created_at":   l.CreatedAt,
+	}
+	if l.ProjectID != nil {
+		doc["project_id"] = *l.ProjectID
+	}
+	if l.ParentID != nil {
+		doc["parent_id"] = *l.ParentID
+	}
+	result, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		l.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Label, error) {
+	var l Label
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&l)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ListByWorkspace returns the workspace's labels. With a projectID, only workspace-wide labels and
+// that project's labels are returned.
+func (r *Repository) ListByWorkspace(ctx context.Context, workspaceID primitive.ObjectID, projectID *primitive.ObjectID) ([]*Label, error) {
+	filter := bson.M{"workspace_id": workspaceID}
+	if projectID != nil {
+		filter["$or"] = []bson.M{
+			{"project_id": bson.M{"$exists": false}},
+			{"project_id": *projectID},
+		}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Label
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) CountChildren(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	return r.col.CountDocuments(ctx, bson.M{"parent_id": id})
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, set, unset bson.M) error {
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if len(update) == 0 {
+		return nil
+	}
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// Ungroup detaches every child of the given parent label.
+func (r *Repository) Ungroup(ctx context.Context, parentID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"parent_id": parentID}, bson.M{"$unset": bson.M{"parent_id": ""}})
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// WithTransaction runs fn in a multi-document transaction. ctx passed to fn carries the session;
+// repositories of other collections must use it for their writes to join the transaction.
+func (r *Repository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	sess, err := r.col.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
@@ -0,0 +1,188 @@
+package label
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
+)
+
+// TaskDetacher removes a label from every task. Implemented by task.Service; set on Service
+// after construction to avoid an import cycle.
+type TaskDetacher interface {
+	DetachLabel(ctx context.Context, labelID primitive.ObjectID) error
+}
+
+type Service struct {
+	repo *Repository
+	// Tasks is called inside the delete transaction to detach the label from tasks.
+	Tasks TaskDetacher
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Patch holds optional label fields for Update. ClearParent moves the label out of its group.
+type Patch struct {
+	Name        *string
+	Color       *string
+	ParentID    *primitive.ObjectID
+	ClearParent bool
+}
+
+// Create adds a workspace-wide label, or a project label when projectID is set.
+func (s *Service) Create(ctx context.Context, workspaceID primitive.ObjectID, projectID, parentID *primitive.ObjectID, name, color string) (*Label, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || !validColor(color) {
+		return nil, common.ErrInvalidInput
+	}
+	l := &Label{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		Name:        name,
+		Color:       color,
+		CreatedAt:   time.Now(),
+	}
+	if parentID != nil {
+		if err := s.checkParent(ctx, l, *parentID); err != nil {
+			return nil, err
+		}
+		l.ParentID = parentID
+	}
+	if err := s.repo.Create(ctx, l); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, common.ErrConflict
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// Get returns a label of the workspace.
+func (s *Service) Get(ctx context.Context, workspaceID, id primitive.ObjectID) (*Label, error) {
+	l, err := s.repo.FindByID(ctx, id)
+	if err != nil || l.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	return l, nil
+}
+
+// List returns workspace labels; with projectID, only those usable in that project.
+func (s *Service) List(ctx context.Context, workspaceID primitive.ObjectID, projectID *primitive.ObjectID) ([]*Label, error) {
+	return s.repo.ListByWorkspace(ctx, workspaceID, projectID)
+}
+
+// CheckApplicable returns nil if the label exists in the workspace and may be attached to tasks of projectID.
+func (s *Service) CheckApplicable(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) error {
+	l, err := s.Get(ctx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if !l.AppliesTo(projectID) {
+		return fmt.Errorf("%w: label %s belongs to another project", common.ErrInvalidInput, l.Name)
+	}
+	return nil
+}
+
+// Update renames, recolors or regroups a label.
+func (s *Service) Update(ctx context.Context, workspaceID, id primitive.ObjectID, p Patch) (*Label, error) {
+	l, err := s.Get(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	set, unset := bson.M{}, bson.M{}
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, common.ErrInvalidInput
+		}
+		set["name"] = name
+	}
+	if p.Color != nil {
+		if !validColor(*p.Color) {
+			return nil, common.ErrInvalidInput
+		}
+		set["color"] = *p.Color
+	}
+	switch {
+	case p.ClearParent:
+		unset["parent_id"] = ""
+	case p.ParentID != nil:
+		if err := s.checkParent(ctx, l, *p.ParentID); err != nil {
+			return nil, err
+		}
+		n, err := s.repo.CountChildren(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: a label with children cannot join another group", common.ErrInvalidInput)
+		}
+		set["parent_id"] = *p.ParentID
+	}
+	if err := s.repo.Update(ctx, id, set, unset); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, common.ErrConflict
+		}
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete removes a label, ungroups its children and detaches it from every task in one transaction.
+func (s *Service) Delete(ctx context.Context, workspaceID, id primitive.ObjectID) error {
+	if _, err := s.Get(ctx, workspaceID, id); err != nil {
+		return err
+	}
+	return s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		if s.Tasks != nil {
+			if err := s.Tasks.DetachLabel(ctx, id); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Ungroup(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, id)
+	})
+}
+
+// checkParent validates that parentID can group l: same workspace, top-level, and usable wherever l is.
+func (s *Service) checkParent(ctx context.Context, l *Label, parentID primitive.ObjectID) error {
+	if parentID == l.ID {
+		return common.ErrInvalidInput
+	}
+	parent, err := s.Get(ctx, l.WorkspaceID, parentID)
+	if err != nil {
+		return fmt.Errorf("%w: parent label not found", common.ErrInvalidInput)
+	}
+	if parent.ParentID != nil {
+		return fmt.Errorf("%w: label groups cannot be nested", common.ErrInvalidInput)
+	}
+	if parent.ProjectID != nil && (l.ProjectID == nil || *l.ProjectID != *parent.ProjectID) {
+		return fmt.Errorf("%w: parent label belongs to another project", common.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validColor accepts an empty color or a #RGB / #RRGGBB hex value.
+func validColor(c string) bool {
+	if c == "" {
+		return true
+	}
+	if c[0] != '#' || (len(c) != 4 && len(c) != 7) {
+		return false
+	}
+	for _, r := range c[1:] {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package mention
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ListMine handles GET /me/mentions?cursor=&limit= (newest first).
+func (h *Handler) ListMine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = common.DefaultPageSize
+	}
+	if limit > common.MaxPageSize {
+		limit = common.MaxPageSize
+	}
+	page, err := h.svc.ListForUser(r.Context(), userID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
@@ -0,0 +1,56 @@
+package mention
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Source is where a mention was written.
+type Source string
+
+const (
+	SourceTask    Source = "task"
+	SourceComment Source = "comment"
+)
+
+// Mention records that UserID was mentioned in a task description or comment. Users are stored by ID
+// so mentions survive email changes.
+type Mention struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID  `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID  `bson:"project_id" json:"project_id"`
+	TaskID      primitive.ObjectID  `bson:"task_id" json:"task_id"`
+	CommentID   *primitive.ObjectID `bson:"comment_id,omitempty" json:"comment_id,omitempty"`
+	Source      Source              `bson:"source" json:"source"`
+
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	MentionedBy primitive.ObjectID `bson:"mentioned_by" json:"mentioned_by"`
+	Excerpt     string             `bson:"excerpt" json:"excerpt"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Ref identifies the text being scanned for mentions.
+type Ref struct {
+	WorkspaceID primitive.ObjectID
+	ProjectID   primitive.ObjectID
+	TaskID      primitive.ObjectID
+	CommentID   *primitive.ObjectID // set for comments
+	AuthorID    primitive.ObjectID
+}
+
+// Source returns where the referenced text lives.
+func (r Ref) Source() Source {
+	if r.CommentID != nil {
+		return SourceComment
+	}
+	return SourceTask
+}
+
+// Page is one page of mentions. NextCursor is empty on the last page.
+type Page struct {
+	Items      []*Mention `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
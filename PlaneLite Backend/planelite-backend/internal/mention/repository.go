@@ -0,0 +1,102 @@
+package mention
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("mentions")}
+}
+
+func (r *Repository) Create(ctx context.Context, m *Mention) error {
+	doc := bson.M{
+		"workspace_id": m.WorkspaceID,
+		"project_id":   m.ProjectID,
+		"task_id":      m.TaskID,
+		"source":       m.Source,
+		"user_id":      m.UserID,
+		"mentioned_by": m.MentionedBy,
+		"excerpt":      m.Excerpt,
+		"created_at":   m.CreatedAt,
+	}
+	if m.CommentID != nil {
+		doc["comment_id"] = *m.CommentID
+	}
+	result, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		m.ID = oid
+	}
+	return nil
+}
+
+// ListBySource returns mentions recorded for a task description or a comment.
+func (r *Repository) ListBySource(ctx context.Context, ref Ref) ([]*Mention, error) {
+	cur, err := r.col.Find(ctx, sourceFilter(ref))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Mention
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteUsers removes the source's mentions of the given users.
+func (r *Repository) DeleteUsers(ctx context.Context, ref Ref, userIDs []primitive.ObjectID) error {
+	filter := sourceFilter(ref)
+	filter["user_id"] = bson.M{"$in": userIDs}
+	_, err := r.col.DeleteMany(ctx, filter)
+	return err
+}
+
+// ListByUser returns up to limit mentions of userID, newest first, older than before when set.
+func (r *Repository) ListByUser(ctx context.Context, userID primitive.ObjectID, before *primitive.ObjectID, limit int64) ([]*Mention, error) {
+	filter := bson.M{"user_id": userID}
+	if before != nil {
+		filter["_id"] = bson.M{"$lt": *before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Mention
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteByTask removes mentions made in a task's description or comments.
+func (r *Repository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.col.DeleteMany(ctx, bson.M{"task_id": taskID})
+	return err
+}
+
+// SetProject points the mentions of a task at the task's new project.
+func (r *Repository) SetProject(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"task_id": taskID}, bson.M{"$set": bson.M{"project_id": projectID}})
+	return err
+}
+
+func sourceFilter(ref Ref) bson.M {
+	if ref.CommentID != nil {
+		return bson.M{"source": SourceComment, "comment_id": *ref.CommentID}
+	}
+	return bson.M{"source": SourceTask, "task_id": ref.TaskID}
+}
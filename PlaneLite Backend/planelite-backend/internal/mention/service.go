@@ -0,0 +1,166 @@
+package mention
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/notification"
+	"planelite-backend/internal/user"
+	"planelite-backend/internal/workspace"
+)
+
+// Mentions are written either as "@user@example.com" or as a token "@[user:<id>]". Email mentions
+// of approved members are rewritten to tokens before the text is stored, so they keep resolving
+// after the user changes email.
+var (
+	emailMention = regexp.MustCompile(`@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	tokenMention = regexp.MustCompile(`@\[user:([0-9a-fA-F]{24})\]`)
+)
+
+// maxExcerpt caps the length of the text stored with a mention.
+const maxExcerpt = 140
+
+type Service struct {
+	repo       *Repository
+	users      *user.Service
+	workspaces *workspace.Service
+	notifier   *notification.Service
+}
+
+func NewService(repo *Repository, users *user.Service, workspaces *workspace.Service, notifier *notification.Service) *Service {
+	return &Service{repo: repo, users: users, workspaces: workspaces, notifier: notifier}
+}
+
+// Token returns the stored form of a mention of userID.
+func Token(userID primitive.ObjectID) string {
+	return "@[user:" + userID.Hex() + "]"
+}
+
+// Resolve finds mentions of approved workspace members in text. It returns text with email
+// mentions replaced by tokens, and the mentioned user IDs. Unknown users and non-members are
+// left as plain text.
+func (s *Service) Resolve(ctx context.Context, workspaceID primitive.ObjectID, text string) (string, []primitive.ObjectID) {
+	var ids []primitive.ObjectID
+	seen := map[primitive.ObjectID]bool{}
+	add := func(id primitive.ObjectID) bool {
+		ok, err := s.workspaces.HasApprovedAccess(ctx, id, workspaceID)
+		if err != nil || !ok {
+			return false
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		return true
+	}
+	text = emailMention.ReplaceAllStringFunc(text, func(m string) string {
+		u, err := s.users.FindByEmail(ctx, m[1:])
+		if err != nil || !add(u.ID) {
+			return m
+		}
+		return Token(u.ID)
+	})
+	for _, sub := range tokenMention.FindAllStringSubmatch(text, -1) {
+		if id, err := primitive.ObjectIDFromHex(sub[1]); err == nil {
+			add(id)
+		}
+	}
+	return text, ids
+}
+
+// Sync makes userIDs the set of users mentioned by ref. Users newly mentioned are recorded and
+// notified; users no longer mentioned are dropped. The author is never notified of their own mention.
+func (s *Service) Sync(ctx context.Context, ref Ref, text string, userIDs []primitive.ObjectID) error {
+	existing, err := s.repo.ListBySource(ctx, ref)
+	if err != nil {
+		return err
+	}
+	want := make(map[primitive.ObjectID]bool, len(userIDs))
+	for _, id := range userIDs {
+		want[id] = true
+	}
+	had := make(map[primitive.ObjectID]bool, len(existing))
+	var stale []primitive.ObjectID
+	for _, m := range existing {
+		had[m.UserID] = true
+		if !want[m.UserID] {
+			stale = append(stale, m.UserID)
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.repo.DeleteUsers(ctx, ref, stale); err != nil {
+			return err
+		}
+	}
+	excerpt := excerptOf(text)
+	for _, id := range userIDs {
+		if had[id] || id == ref.AuthorID {
+			continue
+		}
+		m := &Mention{
+			WorkspaceID: ref.WorkspaceID,
+			ProjectID:   ref.ProjectID,
+			TaskID:      ref.TaskID,
+			CommentID:   ref.CommentID,
+			Source:      ref.Source(),
+			UserID:      id,
+			MentionedBy: ref.AuthorID,
+			Excerpt:     excerpt,
+			CreatedAt:   time.Now(),
+		}
+		if err := s.repo.Create(ctx, m); err != nil {
+			return err
+		}
+		if s.notifier != nil {
+			_ = s.notifier.Notify(ctx, id, "You were mentioned", excerpt)
+		}
+	}
+	return nil
+}
+
+// DeleteByTask removes the mentions of a deleted task.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return s.repo.DeleteByTask(ctx, taskID)
+}
+
+// MoveTask follows a task into another project of the same workspace.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	return s.repo.SetProject(ctx, taskID, projectID)
+}
+
+// ListForUser returns a page of mentions of userID, newest first.
+func (s *Service) ListForUser(ctx context.Context, userID primitive.ObjectID, cursor string, limit int) (*Page, error) {
+	var before *primitive.ObjectID
+	if cursor != "" {
+		c, err := common.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		before = &c.ID
+	}
+	// Fetch one extra to know whether another page exists.
+	list, err := s.repo.ListByUser(ctx, userID, before, int64(limit)+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{Items: list}
+	if len(list) > limit {
+		page.Items = list[:limit]
+		page.NextCursor = common.EncodeCursor(common.Cursor{ID: page.Items[limit-1].ID})
+	}
+	if page.Items == nil {
+		page.Items = []*Mention{}
+	}
+	return page, nil
+}
+
+func excerptOf(text string) string {
+	r := []rune(text)
+	if len(r) <= maxExcerpt {
+		return text
+	}
+	return string(r[:maxExcerpt]) + "…"
+}
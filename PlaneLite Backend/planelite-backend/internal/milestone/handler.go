@@ -0,0 +1,295 @@
+package milestone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+const dayLayout = "2006-01-02"
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST /workspaces/:id/milestones.
+type CreateRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	TargetDate  time.Time `json:"target_date"` // RFC 3339
+	ProjectIDs  []string  `json:"project_ids"`
+	ModuleIDs   []string  `json:"module_ids"`
+	TaskIDs     []string  `json:"task_ids"`
+}
+
+// UpdateRequest is the JSON Merge Patch body of PATCH /workspaces/:id/milestones/:msid: absent members
+// are left alone, a link list replaces the links of its kind and null clears it.
+type UpdateRequest struct {
+	Name        common.Optional[string]    `json:"name"`
+	Description common.Optional[string]    `json:"description"`
+	TargetDate  common.Optional[time.Time] `json:"target_date"`
+	ProjectIDs  common.Optional[[]string]  `json:"project_ids"`
+	ModuleIDs   common.Optional[[]string]  `json:"module_ids"`
+	TaskIDs     common.Optional[[]string]  `json:"task_ids"`
+}
+
+// Create handles POST /workspaces/:id/milestones.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	in := Input{Name: req.Name, Description: req.Description, TargetDate: req.TargetDate}
+	if in.ProjectIDs, err = parseIDs(req.ProjectIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	if in.ModuleIDs, err = parseIDs(req.ModuleIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	if in.TaskIDs, err = parseIDs(req.TaskIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	d, err := h.svc.Create(r.Context(), wsID, userID, in)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, d)
+}
+
+// List handles GET /workspaces/:id/milestones (by target date, paged; see common.ParseListParams).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.List(r.Context(), wsID, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// GetByID handles GET /workspaces/:id/milestones/:msid, with progress.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, id, ok := milestonePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	d, err := h.svc.Get(r.Context(), wsID, id)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// Update handles PATCH /workspaces/:id/milestones/:msid.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, id, ok := milestonePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := common.CheckMergePatch(r); err != nil {
+		common.Error(w, err)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if req.TargetDate.Null {
+		common.Error(w, fmt.Errorf("%w: target_date cannot be empty", common.ErrInvalidInput))
+		return
+	}
+	p := Patch{Name: req.Name.Ptr(), Description: req.Description.Ptr(), TargetDate: req.TargetDate.Ptr()}
+	var err error
+	if p.ProjectIDs, err = parseIDPatch(req.ProjectIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	if p.ModuleIDs, err = parseIDPatch(req.ModuleIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	if p.TaskIDs, err = parseIDPatch(req.TaskIDs); err != nil {
+		common.Error(w, err)
+		return
+	}
+	d, err := h.svc.Update(r.Context(), wsID, id, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// Delete handles DELETE /workspaces/:id/milestones/:msid.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, id, ok := milestonePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, id); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// Roadmap handles GET /workspaces/:id/roadmap?from=&to= (YYYY-MM-DD, UTC; to is exclusive). It
+// returns the milestones by target date, each with its progress and at-risk flag.
+func (h *Handler) Roadmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	from, err := parseDay(q.Get("from"))
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	to, err := parseDay(q.Get("to"))
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	rm, err := h.svc.Roadmap(r.Context(), wsID, from, to)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, rm)
+}
+
+// manager returns the caller's ID if they may manage milestones, replying 401 or 403 otherwise.
+func manager(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	if !CanManageMilestone(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// milestonePath parses the workspace and milestone IDs from the route.
+func milestonePath(r *http.Request) (wsID, id primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, id, false
+	}
+	if id, err = primitive.ObjectIDFromHex(r.PathValue("msid")); err != nil {
+		return wsID, id, false
+	}
+	return wsID, id, true
+}
+
+// parseIDs parses hex object IDs.
+func parseIDs(values []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid id %q", common.ErrBadRequest, v)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseIDPatch parses a link list of a merge patch: nil when absent, empty when null.
+func parseIDPatch(o common.Optional[[]string]) (*[]primitive.ObjectID, error) {
+	if !o.Set {
+		return nil, nil
+	}
+	ids, err := parseIDs(o.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &ids, nil
+}
+
+// parseDay parses an optional YYYY-MM-DD query value as UTC midnight.
+func parseDay(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	d, err := time.Parse(dayLayout, v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: dates must be YYYY-MM-DD", common.ErrInvalidInput)
+	}
+	return &d, nil
+}
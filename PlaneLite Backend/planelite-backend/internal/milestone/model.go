@@ -0,0 +1,61 @@
+package milestone
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Milestone is a workspace-level target date. It covers the tasks of its projects and modules and
+// the tasks linked to it directly.
+type Milestone struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID   `bson:"workspace_id" json:"workspace_id"`
+	Name        string               `bson:"name" json:"name"`
+	Description string               `bson:"description" json:"description"`
+	TargetDate  time.Time            `bson:"target_date" json:"target_date"`
+	ProjectIDs  []primitive.ObjectID `bson:"project_ids" json:"project_ids"`
+	ModuleIDs   []primitive.ObjectID `bson:"module_ids" json:"module_ids"`
+	TaskIDs     []primitive.ObjectID `bson:"task_ids" json:"task_ids"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Progress rolls up the live, unarchived tasks a milestone covers; each task counts once however
+// it is linked. Done means a closed (completed or cancelled) status of the task's project.
+type Progress struct {
+	TotalTasks int `json:"total_tasks"`
+	DoneTasks  int `json:"done_tasks"`
+	Percent    int `json:"percent"` // DoneTasks of TotalTasks, rounded down; 0 without tasks
+	// LateTasks counts the open tasks due after the target date.
+	LateTasks int `json:"late_tasks"`
+	// AtRisk is set while an open task is due after the target date.
+	AtRisk bool `json:"at_risk"`
+	// Overdue is set once the target date has passed with tasks still open.
+	Overdue bool `json:"overdue"`
+}
+
+// derive fills in the fields computed from the counts.
+func (p *Progress) derive(target, now time.Time) {
+	if p.TotalTasks > 0 {
+		p.Percent = p.DoneTasks * 100 / p.TotalTasks
+	}
+	p.AtRisk = p.LateTasks > 0
+	p.Overdue = now.After(target) && p.DoneTasks < p.TotalTasks
+}
+
+// Detail is a milestone with its progress.
+type Detail struct {
+	*Milestone
+	Progress Progress `json:"progress"`
+}
+
+// Roadmap is the workspace's milestones in a date range, by target date.
+type Roadmap struct {
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	Milestones []*Detail  `json:"milestones"`
+}
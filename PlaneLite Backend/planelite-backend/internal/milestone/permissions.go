@@ -0,0 +1,11 @@
+package milestone
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageMilestone: admin and PROJECT_MANAGER can create, change and delete milestones and change
+// what they link to.
+func CanManageMilestone(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
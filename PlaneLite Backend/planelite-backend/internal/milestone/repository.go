@@ -0,0 +1,91 @@
+package milestone
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"planelite-backend/internal/common"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("milestones")}
+}
+
+func (r *Repository) Create(ctx context.Context, m *Milestone) error {
+	result, err := r.col.InsertOne(ctx, m)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		m.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Milestone, error) {
+	var m Milestone
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListPage returns one page of the workspace's milestones by target date.
+func (r *Repository) ListPage(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Milestone], error) {
+	sort := bson.D{{Key: "target_date", Value: 1}, {Key: "_id", Value: 1}}
+	return common.FindPage[Milestone](ctx, r.col, bson.M{"workspace_id": workspaceID}, sort, p)
+}
+
+// ListRange returns up to limit of the workspace's milestones with target dates in [from, to), by
+// target date; a nil bound is open.
+func (r *Repository) ListRange(ctx context.Context, workspaceID primitive.ObjectID, from, to *time.Time, limit int64) ([]*Milestone, error) {
+	filter := bson.M{"workspace_id": workspaceID}
+	dates := bson.M{}
+	if from != nil {
+		dates["$gte"] = *from
+	}
+	if to != nil {
+		dates["$lt"] = *to
+	}
+	if len(dates) > 0 {
+		filter["target_date"] = dates
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "target_date", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cur, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var list []*Milestone
+	if err := cur.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// Unlink removes id from the field (project_ids, module_ids or task_ids) of every milestone.
+func (r *Repository) Unlink(ctx context.Context, field string, id primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{field: id}, bson.M{
+		"$pull": bson.M{field: id},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
@@ -0,0 +1,264 @@
+package milestone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+)
+
+const (
+	// MaxLinks bounds each of a milestone's project, module and task lists.
+	MaxLinks = 100
+	// MaxRoadmap bounds the milestones of one roadmap.
+	MaxRoadmap = 200
+)
+
+// Tasks is what milestones need from tasks. Implemented by task.Service.
+type Tasks interface {
+	CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error
+	// MilestoneProgress counts the tasks the milestone covers: TotalTasks, DoneTasks and LateTasks.
+	MilestoneProgress(ctx context.Context, m *Milestone) (Progress, error)
+}
+
+// Modules checks module links. Implemented by module.Service.
+type Modules interface {
+	CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error
+}
+
+type Service struct {
+	repo     *Repository
+	tasks    Tasks
+	modules  Modules
+	projects *project.Service
+}
+
+func NewService(repo *Repository, tasks Tasks, modules Modules, projects *project.Service) *Service {
+	return &Service{repo: repo, tasks: tasks, modules: modules, projects: projects}
+}
+
+// Input holds a new milestone.
+type Input struct {
+	Name        string
+	Description string
+	TargetDate  time.Time
+	ProjectIDs  []primitive.ObjectID
+	ModuleIDs   []primitive.ObjectID
+	TaskIDs     []primitive.ObjectID
+}
+
+// Patch lists the changes to a milestone; nil fields are left alone and a non-nil list replaces the
+// links of its kind.
+type Patch struct {
+	Name        *string
+	Description *string
+	TargetDate  *time.Time
+	ProjectIDs  *[]primitive.ObjectID
+	ModuleIDs   *[]primitive.ObjectID
+	TaskIDs     *[]primitive.ObjectID
+}
+
+// Create adds a milestone to the workspace.
+func (s *Service) Create(ctx context.Context, workspaceID, userID primitive.ObjectID, in Input) (*Detail, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+	if in.TargetDate.IsZero() {
+		return nil, fmt.Errorf("%w: target_date is required", common.ErrInvalidInput)
+	}
+	var err error
+	if in.ProjectIDs, err = s.checkProjects(ctx, workspaceID, in.ProjectIDs); err != nil {
+		return nil, err
+	}
+	if in.ModuleIDs, err = s.checkLinks(ctx, workspaceID, in.ModuleIDs, s.modules.CheckInWorkspace); err != nil {
+		return nil, err
+	}
+	if in.TaskIDs, err = s.checkLinks(ctx, workspaceID, in.TaskIDs, s.tasks.CheckInWorkspace); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	m := &Milestone{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Description: in.Description,
+		TargetDate:  in.TargetDate,
+		ProjectIDs:  in.ProjectIDs,
+		ModuleIDs:   in.ModuleIDs,
+		TaskIDs:     in.TaskIDs,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, m, now)
+}
+
+// Get returns a milestone of the workspace with its progress.
+func (s *Service) Get(ctx context.Context, workspaceID, id primitive.ObjectID) (*Detail, error) {
+	m, err := s.find(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, m, time.Now())
+}
+
+// List returns one page of the workspace's milestones by target date.
+func (s *Service) List(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Milestone], error) {
+	return s.repo.ListPage(ctx, workspaceID, p)
+}
+
+// Roadmap returns the workspace's milestones with target dates in [from, to) by target date, each with
+// its progress; a nil bound is open. At most MaxRoadmap milestones are returned.
+func (s *Service) Roadmap(ctx context.Context, workspaceID primitive.ObjectID, from, to *time.Time) (*Roadmap, error) {
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, fmt.Errorf("%w: to must be after from", common.ErrInvalidInput)
+	}
+	list, err := s.repo.ListRange(ctx, workspaceID, from, to, MaxRoadmap)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := &Roadmap{From: from, To: to, Milestones: make([]*Detail, 0, len(list))}
+	for _, m := range list {
+		d, err := s.detail(ctx, m, now)
+		if err != nil {
+			return nil, err
+		}
+		out.Milestones = append(out.Milestones, d)
+	}
+	return out, nil
+}
+
+// Update changes a milestone of the workspace.
+func (s *Service) Update(ctx context.Context, workspaceID, id primitive.ObjectID, p Patch) (*Detail, error) {
+	if _, err := s.find(ctx, workspaceID, id); err != nil {
+		return nil, err
+	}
+	set := bson.M{"updated_at": time.Now()}
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", common.ErrInvalidInput)
+		}
+		set["name"] = name
+	}
+	if p.Description != nil {
+		set["description"] = *p.Description
+	}
+	if p.TargetDate != nil {
+		if p.TargetDate.IsZero() {
+			return nil, fmt.Errorf("%w: target_date cannot be empty", common.ErrInvalidInput)
+		}
+		set["target_date"] = *p.TargetDate
+	}
+	if p.ProjectIDs != nil {
+		ids, err := s.checkProjects(ctx, workspaceID, *p.ProjectIDs)
+		if err != nil {
+			return nil, err
+		}
+		set["project_ids"] = ids
+	}
+	if p.ModuleIDs != nil {
+		ids, err := s.checkLinks(ctx, workspaceID, *p.ModuleIDs, s.modules.CheckInWorkspace)
+		if err != nil {
+			return nil, err
+		}
+		set["module_ids"] = ids
+	}
+	if p.TaskIDs != nil {
+		ids, err := s.checkLinks(ctx, workspaceID, *p.TaskIDs, s.tasks.CheckInWorkspace)
+		if err != nil {
+			return nil, err
+		}
+		set["task_ids"] = ids
+	}
+	if err := s.repo.Update(ctx, id, set); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, workspaceID, id)
+}
+
+// Delete removes a milestone; what it links to is left alone.
+func (s *Service) Delete(ctx context.Context, workspaceID, id primitive.ObjectID) error {
+	if _, err := s.find(ctx, workspaceID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// DeleteByTask unlinks a purged task. Implements task.Dependent.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return s.repo.Unlink(ctx, "task_ids", taskID)
+}
+
+// MoveTask keeps a moved task linked: tasks only move within their workspace. Implements
+// task.Dependent.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	return nil
+}
+
+// DeleteByModule unlinks a deleted module. Implements module.Dependent.
+func (s *Service) DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error {
+	return s.repo.Unlink(ctx, "module_ids", moduleID)
+}
+
+// detail adds the progress to a milestone.
+func (s *Service) detail(ctx context.Context, m *Milestone, now time.Time) (*Detail, error) {
+	p, err := s.tasks.MilestoneProgress(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	p.derive(m.TargetDate, now)
+	return &Detail{Milestone: m, Progress: p}, nil
+}
+
+// checkProjects verifies the projects belong to the workspace and returns them without duplicates.
+func (s *Service) checkProjects(ctx context.Context, workspaceID primitive.ObjectID, ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return s.checkLinks(ctx, workspaceID, ids, func(ctx context.Context, workspaceID, id primitive.ObjectID) error {
+		p, err := s.projects.GetByID(ctx, id)
+		if err != nil || p.WorkspaceID != workspaceID {
+			return common.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// checkLinks drops duplicate IDs, bounds the list by MaxLinks and checks each ID with check. The
+// result is never nil, so the stored list is an empty array rather than null.
+func (s *Service) checkLinks(ctx context.Context, workspaceID primitive.ObjectID, ids []primitive.ObjectID, check func(ctx context.Context, workspaceID, id primitive.ObjectID) error) ([]primitive.ObjectID, error) {
+	out := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	if len(out) > MaxLinks {
+		return nil, fmt.Errorf("%w: link at most %d of each kind", common.ErrInvalidInput, MaxLinks)
+	}
+	for _, id := range out {
+		if err := check(ctx, workspaceID, id); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// find returns a milestone of the workspace.
+func (s *Service) find(ctx context.Context, workspaceID, id primitive.ObjectID) (*Milestone, error) {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil || m.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	return m, nil
+}
@@ -0,0 +1,296 @@
+package module
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST /workspaces/:id/projects/:pid/modules.
+type CreateRequest struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	LeadID      string     `json:"lead_id"`     // optional; an approved workspace member
+	TargetDate  *time.Time `json:"target_date"` // optional, RFC 3339
+	Status      Status     `json:"status"`      // optional; backlog by default
+}
+
+// UpdateRequest is the JSON Merge Patch body of PATCH .../modules/:mid: absent members are left alone
+// and null clears lead_id or target_date.
+type UpdateRequest struct {
+	Name        common.Optional[string]    `json:"name"`
+	Description common.Optional[string]    `json:"description"`
+	LeadID      common.Optional[string]    `json:"lead_id"`
+	TargetDate  common.Optional[time.Time] `json:"target_date"`
+	Status      common.Optional[Status]    `json:"status"`
+}
+
+// AddTasksRequest is the JSON body for POST .../modules/:mid/tasks.
+type AddTasksRequest struct {
+	TaskIDs []string `json:"task_ids"`
+}
+
+// Create handles POST /workspaces/:id/projects/:pid/modules.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := manager(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, ok := projectPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	in := Input{Name: req.Name, Description: req.Description, TargetDate: req.TargetDate, Status: req.Status}
+	if req.LeadID != "" {
+		lead, err := primitive.ObjectIDFromHex(req.LeadID)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		in.LeadID = &lead
+	}
+	m, err := h.svc.Create(r.Context(), wsID, pid, userID, in)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, m)
+}
+
+// List handles GET /workspaces/:id/projects/:pid/modules?status= (by name, paged; see
+// common.ParseListParams). Each module comes with its progress.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, ok := projectPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.List(r.Context(), wsID, pid, Status(r.URL.Query().Get("status")), p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// GetByID handles GET /workspaces/:id/projects/:pid/modules/:mid, with progress.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, mid, ok := modulePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	d, err := h.svc.Get(r.Context(), wsID, pid, mid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// Update handles PATCH /workspaces/:id/projects/:pid/modules/:mid.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, mid, ok := modulePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := common.CheckMergePatch(r); err != nil {
+		common.Error(w, err)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p := Patch{
+		Name:            req.Name.Ptr(),
+		Description:     req.Description.Ptr(),
+		ClearLead:       req.LeadID.Null,
+		TargetDate:      req.TargetDate.Ptr(),
+		ClearTargetDate: req.TargetDate.Null,
+		Status:          req.Status.Ptr(),
+	}
+	if v := req.LeadID.Ptr(); v != nil {
+		lead, err := primitive.ObjectIDFromHex(*v)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		p.LeadID = &lead
+	}
+	m, err := h.svc.Update(r.Context(), wsID, pid, mid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, m)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/modules/:mid.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, mid, ok := modulePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, pid, mid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// AddTasks handles POST /workspaces/:id/projects/:pid/modules/:mid/tasks.
+func (h *Handler) AddTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, mid, ok := modulePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req AddTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(req.TaskIDs))
+	for _, v := range req.TaskIDs {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	d, err := h.svc.AddTasks(r.Context(), wsID, pid, mid, ids)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, d)
+}
+
+// RemoveTask handles DELETE /workspaces/:id/projects/:pid/modules/:mid/tasks/:tid.
+func (h *Handler) RemoveTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if _, ok := manager(w, r); !ok {
+		return
+	}
+	wsID, pid, mid, ok := modulePath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.RemoveTask(r.Context(), wsID, pid, mid, tid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// manager returns the caller's ID if they may manage modules, replying 401 or 403 otherwise.
+func manager(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	if !CanManageModule(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// projectPath parses the workspace and project IDs from the route.
+func projectPath(r *http.Request) (wsID, pid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, false
+	}
+	return wsID, pid, true
+}
+
+// modulePath parses the workspace, project and module IDs from the route.
+func modulePath(r *http.Request) (wsID, pid, mid primitive.ObjectID, ok bool) {
+	if wsID, pid, ok = projectPath(r); !ok {
+		return wsID, pid, mid, false
+	}
+	var err error
+	if mid, err = primitive.ObjectIDFromHex(r.PathValue("mid")); err != nil {
+		return wsID, pid, mid, false
+	}
+	return wsID, pid, mid, true
+}
@@ -0,0 +1,61 @@
+package module
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is where a module stands; set by hand, unlike cycle statuses.
+type Status string
+
+const (
+	StatusBacklog    Status = "backlog"
+	StatusPlanned    Status = "planned"
+	StatusInProgress Status = "in_progress"
+	StatusPaused     Status = "paused"
+	StatusCompleted  Status = "completed"
+	StatusCancelled  Status = "cancelled"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusBacklog, StatusPlanned, StatusInProgress, StatusPaused, StatusCompleted, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Module (epic) groups tasks of a project around a feature or goal. Tasks join through
+// task.Task.ModuleIDs and may be in several modules.
+type Module struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID  `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID  `bson:"project_id" json:"project_id"`
+	Name        string              `bson:"name" json:"name"`
+	Description string              `bson:"description" json:"description"`
+	LeadID      *primitive.ObjectID `bson:"lead_id,omitempty" json:"lead_id,omitempty"`
+	TargetDate  *time.Time          `bson:"target_date,omitempty" json:"target_date,omitempty"`
+	Status      Status              `bson:"status" json:"status"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Progress rolls up a module's live, unarchived tasks: how many are closed (completed or cancelled)
+// and the estimates of those against all.
+type Progress struct {
+	DoneTasks   int     `json:"done_tasks"`
+	TotalTasks  int     `json:"total_tasks"`
+	DonePoints  float64 `json:"done_points"`
+	TotalPoints float64 `json:"total_points"`
+}
+
+// Detail is a module with its progress.
+type Detail struct {
+	*Module
+	Progress Progress `json:"progress"`
+}
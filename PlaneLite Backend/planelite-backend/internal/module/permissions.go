@@ -0,0 +1,11 @@
+package module
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageModule: admin and PROJECT_MANAGER can create, change and delete modules and change which
+// tasks they hold.
+func CanManageModule(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
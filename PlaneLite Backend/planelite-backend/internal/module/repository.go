@@ -0,0 +1,66 @@
+package module
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("modules")}
+}
+
+func (r *Repository) Create(ctx context.Context, m *Module) error {
+	result, err := r.col.InsertOne(ctx, m)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		m.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Module, error) {
+	var m Module
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListPage returns one page of the project's modules, optionally only those in status, by name.
+func (r *Repository) ListPage(ctx context.Context, projectID primitive.ObjectID, status Status, p common.ListParams) (*common.ListPage[*Module], error) {
+	filter := bson.M{"project_id": projectID}
+	if status != "" {
+		filter["status"] = status
+	}
+	sort := bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}
+	return common.FindPage[Module](ctx, r.col, filter, sort, p)
+}
+
+// Patch sets and removes fields of a module.
+func (r *Repository) Patch(ctx context.Context, id primitive.ObjectID, set bson.M, unset []string) error {
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, f := range unset {
+			fields[f] = ""
+		}
+		update["$unset"] = fields
+	}
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
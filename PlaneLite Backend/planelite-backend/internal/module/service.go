@@ -0,0 +1,279 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+	"planelite-backend/internal/workspace"
+)
+
+// MaxTasksPerRequest bounds the task IDs of one AddTasks call.
+const MaxTasksPerRequest = 100
+
+// Tasks is what modules need from tasks. Implemented by task.Service.
+type Tasks interface {
+	CheckInProject(ctx context.Context, projectID, id primitive.ObjectID) error
+	// AddToModule and RemoveFromModule change a task's module memberships.
+	AddToModule(ctx context.Context, ids []primitive.ObjectID, moduleID primitive.ObjectID) error
+	RemoveFromModule(ctx context.Context, id, moduleID primitive.ObjectID) error
+	// ClearModule takes every task out of a deleted module.
+	ClearModule(ctx context.Context, moduleID primitive.ObjectID) error
+	// ModuleProgress rolls up the tasks of each of the project's modules.
+	ModuleProgress(ctx context.Context, projectID primitive.ObjectID, moduleIDs []primitive.ObjectID) (map[primitive.ObjectID]Progress, error)
+}
+
+// Dependent is a service holding links to modules, told when a module is deleted.
+type Dependent interface {
+	DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error
+}
+
+type Service struct {
+	repo       *Repository
+	tasks      Tasks
+	projects   *project.Service
+	workspaces *workspace.Service
+
+	// Dependents are told about deleted modules; set after construction to avoid import cycles.
+	Dependents []Dependent
+}
+
+func NewService(repo *Repository, tasks Tasks, projects *project.Service, workspaces *workspace.Service) *Service {
+	return &Service{repo: repo, tasks: tasks, projects: projects, workspaces: workspaces}
+}
+
+// Input holds a new module; Status defaults to backlog.
+type Input struct {
+	Name        string
+	Description string
+	LeadID      *primitive.ObjectID
+	TargetDate  *time.Time
+	Status      Status
+}
+
+// Patch lists the changes to a module; nil fields are left alone and ClearLead/ClearTargetDate
+// remove the lead or target date.
+type Patch struct {
+	Name            *string
+	Description     *string
+	LeadID          *primitive.ObjectID
+	ClearLead       bool
+	TargetDate      *time.Time
+	ClearTargetDate bool
+	Status          *Status
+}
+
+// Create adds a module to the project.
+func (s *Service) Create(ctx context.Context, workspaceID, projectID, userID primitive.ObjectID, in Input) (*Module, error) {
+	p, err := s.projects.GetByID(ctx, projectID)
+	if err != nil || p.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", common.ErrInvalidInput)
+	}
+	if in.Status == "" {
+		in.Status = StatusBacklog
+	}
+	if !in.Status.Valid() {
+		return nil, errUnknownStatus
+	}
+	if in.LeadID != nil {
+		if err := s.checkLead(ctx, workspaceID, *in.LeadID); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	m := &Module{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		Name:        name,
+		Description: in.Description,
+		LeadID:      in.LeadID,
+		TargetDate:  in.TargetDate,
+		Status:      in.Status,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Get returns a module of the project with its progress.
+func (s *Service) Get(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Detail, error) {
+	m, err := s.find(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.tasks.ModuleProgress(ctx, projectID, []primitive.ObjectID{id})
+	if err != nil {
+		return nil, err
+	}
+	return &Detail{Module: m, Progress: progress[id]}, nil
+}
+
+// List returns one page of the project's modules with their progress, by name. A non-empty status
+// lists only modules in it.
+func (s *Service) List(ctx context.Context, workspaceID, projectID primitive.ObjectID, status Status, p common.ListParams) (*common.ListPage[*Detail], error) {
+	proj, err := s.projects.GetByID(ctx, projectID)
+	if err != nil || proj.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	if status != "" && !status.Valid() {
+		return nil, errUnknownStatus
+	}
+	page, err := s.repo.ListPage(ctx, projectID, status, p)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(page.Items))
+	for i, m := range page.Items {
+		ids[i] = m.ID
+	}
+	progress, err := s.tasks.ModuleProgress(ctx, projectID, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := &common.ListPage[*Detail]{
+		Items:      make([]*Detail, len(page.Items)),
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		TotalCount: page.TotalCount,
+		NextCursor: page.NextCursor,
+	}
+	for i, m := range page.Items {
+		out.Items[i] = &Detail{Module: m, Progress: progress[m.ID]}
+	}
+	return out, nil
+}
+
+// Update changes a module of the project.
+func (s *Service) Update(ctx context.Context, workspaceID, projectID, id primitive.ObjectID, p Patch) (*Module, error) {
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	set := bson.M{"updated_at": time.Now()}
+	var unset []string
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name cannot be empty", common.ErrInvalidInput)
+		}
+		set["name"] = name
+	}
+	if p.Description != nil {
+		set["description"] = *p.Description
+	}
+	switch {
+	case p.ClearLead:
+		unset = append(unset, "lead_id")
+	case p.LeadID != nil:
+		if err := s.checkLead(ctx, workspaceID, *p.LeadID); err != nil {
+			return nil, err
+		}
+		set["lead_id"] = *p.LeadID
+	}
+	switch {
+	case p.ClearTargetDate:
+		unset = append(unset, "target_date")
+	case p.TargetDate != nil:
+		set["target_date"] = *p.TargetDate
+	}
+	if p.Status != nil {
+		if !p.Status.Valid() {
+			return nil, errUnknownStatus
+		}
+		set["status"] = *p.Status
+	}
+	if err := s.repo.Patch(ctx, id, set, unset); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete removes a module; its tasks stay, outside it.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) error {
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	if err := s.tasks.ClearModule(ctx, id); err != nil {
+		return err
+	}
+	for _, d := range s.Dependents {
+		if err := d.DeleteByModule(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// AddTasks adds tasks of the project to a module; tasks already in it are left as they are.
+func (s *Service) AddTasks(ctx context.Context, workspaceID, projectID, id primitive.ObjectID, taskIDs []primitive.ObjectID) (*Detail, error) {
+	if len(taskIDs) == 0 || len(taskIDs) > MaxTasksPerRequest {
+		return nil, fmt.Errorf("%w: give 1 to %d task ids", common.ErrInvalidInput, MaxTasksPerRequest)
+	}
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	for _, tid := range taskIDs {
+		if err := s.tasks.CheckInProject(ctx, projectID, tid); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.tasks.AddToModule(ctx, taskIDs, id); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, workspaceID, projectID, id)
+}
+
+// RemoveTask takes a task out of a module.
+func (s *Service) RemoveTask(ctx context.Context, workspaceID, projectID, id, taskID primitive.ObjectID) error {
+	if _, err := s.find(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	if err := s.tasks.CheckInProject(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	return s.tasks.RemoveFromModule(ctx, taskID, id)
+}
+
+// CheckInWorkspace returns ErrNotFound unless the module exists in the workspace.
+func (s *Service) CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil || m.WorkspaceID != workspaceID {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+var errUnknownStatus = fmt.Errorf("%w: status must be backlog, planned, in_progress, paused, completed or cancelled", common.ErrInvalidInput)
+
+// checkLead verifies that the lead is an approved member of the workspace.
+func (s *Service) checkLead(ctx context.Context, workspaceID, userID primitive.ObjectID) error {
+	ok, err := s.workspaces.HasApprovedAccess(ctx, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: lead must be an approved workspace member", common.ErrInvalidInput)
+	}
+	return nil
+}
+
+// find returns a module of the project in the workspace.
+func (s *Service) find(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Module, error) {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil || m.WorkspaceID != workspaceID || m.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	return m, nil
+}
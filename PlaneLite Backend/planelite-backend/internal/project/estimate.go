@@ -0,0 +1,105 @@
+package project
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"planelite-backend/internal/common"
+)
+
+// EstimateScheme is how the tasks of a project are estimated. The empty scheme turns estimates off.
+type EstimateScheme string
+
+const (
+	EstimateNone   EstimateScheme = ""
+	EstimatePoints EstimateScheme = "points" // Fibonacci story points
+	EstimateTShirt EstimateScheme = "tshirt" // XS to XL
+	EstimateHours  EstimateScheme = "hours"  // any amount up to maxEstimateHours
+)
+
+// maxEstimateHours bounds hour estimates; anything larger is a typo or should be split up.
+const maxEstimateHours = 1000
+
+var errUnknownScheme = fmt.Errorf("%w: estimate_scheme must be points, tshirt, hours or empty", common.ErrInvalidInput)
+
+// EstimatePoint is one step of a fixed scale. Tasks store Value; clients show Label.
+type EstimatePoint struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+var estimateScales = map[EstimateScheme][]EstimatePoint{
+	EstimatePoints: {{"0", 0}, {"1", 1}, {"2", 2}, {"3", 3}, {"5", 5}, {"8", 8}, {"13", 13}, {"21", 21}},
+	// T-shirt sizes carry point values so they can be summed.
+	EstimateTShirt: {{"XS", 1}, {"S", 2}, {"M", 3}, {"L", 5}, {"XL", 8}},
+}
+
+// Valid reports whether s is a known scheme (or none).
+func (s EstimateScheme) Valid() bool {
+	return s == EstimateNone || s == EstimateHours || estimateScales[s] != nil
+}
+
+// Scale returns the fixed steps of the scheme, or nil for hours and none.
+func (s EstimateScheme) Scale() []EstimatePoint {
+	return estimateScales[s]
+}
+
+// Parse turns a label ("M", "8") or, for hours, a number ("2.5") into the value stored on a task.
+func (s EstimateScheme) Parse(v string) (float64, error) {
+	v = strings.TrimSpace(v)
+	switch s {
+	case EstimateNone:
+		return 0, fmt.Errorf("%w: estimates are turned off for this project", common.ErrInvalidInput)
+	case EstimateHours:
+		h, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(h) || math.IsInf(h, 0) || h < 0 || h > maxEstimateHours {
+			return 0, fmt.Errorf("%w: estimate must be between 0 and %d hours", common.ErrInvalidInput, maxEstimateHours)
+		}
+		return h, nil
+	}
+	for _, p := range s.Scale() {
+		if strings.EqualFold(p.Label, v) {
+			return p.Value, nil
+		}
+	}
+	labels := make([]string, 0, len(s.Scale()))
+	for _, p := range s.Scale() {
+		labels = append(labels, p.Label)
+	}
+	return 0, fmt.Errorf("%w: estimate must be one of %s", common.ErrInvalidInput, strings.Join(labels, ", "))
+}
+
+// Fits reports whether a stored estimate is a value of the scheme; used when tasks change project.
+func (s EstimateScheme) Fits(v float64) bool {
+	switch s {
+	case EstimateNone:
+		return false
+	case EstimateHours:
+		return v >= 0 && v <= maxEstimateHours
+	}
+	for _, p := range s.Scale() {
+		if p.Value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateTotal sums the estimates of the project's tasks in one status. Tasks counts all live,
+// unarchived tasks in the status; Estimated those with an estimate.
+type EstimateTotal struct {
+	Status    string  `json:"status"`
+	Tasks     int     `json:"tasks"`
+	Estimated int     `json:"estimated"`
+	Total     float64 `json:"total"`
+}
+
+// EstimateSummary is the estimate section of a project response.
+type EstimateSummary struct {
+	Scheme   EstimateScheme  `json:"scheme"`
+	Scale    []EstimatePoint `json:"scale,omitempty"`
+	ByStatus []EstimateTotal `json:"by_status"`
+	Total    float64         `json:"total"`
+}
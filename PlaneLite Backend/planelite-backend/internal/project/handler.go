@@ -17,7 +17,18 @@ func NewHandler(svc *Service) *Handler {
 }
 
 type CreateRequest struct {
-	Name string `json:"name"`
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"` // optional; derived from the name when empty
+	// EstimateScheme is points, tshirt or hours; optional, estimates are off when empty.
+	EstimateScheme EstimateScheme `json:"estimate_scheme"`
+}
+
+// UpdateRequest is the JSON body for PATCH /workspaces/:id/projects/:pid. Empty fields are left
+// unchanged, except estimate_scheme where "" turns estimates off and only an absent field is ignored.
+type UpdateRequest struct {
+	Name           string          `json:"name"`
+	Identifier     string          `json:"identifier"`
+	EstimateScheme *EstimateScheme `json:"estimate_scheme"`
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +46,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	p, err := h.svc.Create(r.Context(), wsID, req.Name)
+	p, err := h.svc.Create(r.Context(), wsID, req.Name, req.Identifier, req.EstimateScheme)
 	if err != nil {
 		common.Error(w, err)
 		return
@@ -43,6 +54,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	common.Created(w, p)
 }
 
+// GetByID handles GET /workspaces/:id/projects/:pid; the response includes estimate totals per status.
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		common.Error(w, common.ErrBadRequest)
@@ -58,7 +70,7 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	p, err := h.svc.GetByID(r.Context(), id)
+	p, err := h.svc.GetDetail(r.Context(), id)
 	if err != nil {
 		common.Error(w, common.ErrNotFound)
 		return
@@ -66,6 +78,43 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	common.OK(w, p)
 }
 
+// Update handles PATCH /workspaces/:id/projects/:pid. Changing the identifier re-keys the project's
+// tasks; old keys keep resolving.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageProject(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	pid, err := primitive.ObjectIDFromHex(r.PathValue("pid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := h.svc.Update(r.Context(), wsID, pid, req.Name, req.Identifier, req.EstimateScheme)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, p)
+}
+
+// ListByWorkspace handles GET /workspaces/:id/projects. It returns every project unless paging is
+// requested (see common.WantsPage).
 func (h *Handler) ListByWorkspace(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		common.Error(w, common.ErrBadRequest)
@@ -76,6 +125,20 @@ func (h *Handler) ListByWorkspace(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
+	if common.WantsPage(r) {
+		p, err := common.ParseListParams(r)
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		page, err := h.svc.List(r.Context(), wsID, p)
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		common.OK(w, page)
+		return
+	}
 	list, err := h.svc.ListByWorkspace(r.Context(), wsID)
 	if err != nil {
 		common.Error(w, err)
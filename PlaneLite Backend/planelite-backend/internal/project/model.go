@@ -10,5 +10,17 @@ type Project struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"`
 	Name        string             `bson:"name"`
 	WorkspaceID primitive.ObjectID `bson:"workspace_id"`
-	CreatedAt   time.Time          `bson:"created_at"`
+	// Identifier prefixes task keys (WEB in WEB-123); unique within the workspace. Identifiers the project
+	// had before a rename stay in PreviousIdentifiers so old keys keep resolving.
+	Identifier          string   `bson:"identifier"`
+	PreviousIdentifiers []string `bson:"previous_identifiers,omitempty"`
+	// EstimateScheme is how the project's tasks are estimated; empty when estimates are off.
+	EstimateScheme EstimateScheme `bson:"estimate_scheme,omitempty"`
+	CreatedAt      time.Time      `bson:"created_at"`
+}
+
+// Detail is a project as returned by GetByID: the project plus its estimate totals.
+type Detail struct {
+	*Project
+	Estimates *EstimateSummary
 }
@@ -0,0 +1,10 @@
+package project
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageProject: admin and PROJECT_MANAGER can rename projects and change their identifier.
+func CanManageProject(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
 )
 
 type Repository struct {
@@ -17,8 +18,14 @@ func NewRepository(db *mongo.Database) *Repository {
 }
 
 func (r *Repository) Create(ctx context.Context, p *Project) error {
-	_, err := r.col.InsertOne(ctx, p)
-	return err
+	result, err := r.col.InsertOne(ctx, p)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		p.ID = oid
+	}
+	return nil
 }
 
 func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Project, error) {
@@ -42,3 +49,44 @@ func (r *Repository) ListByWorkspace(ctx context.Context, workspaceID primitive.
 	}
 	return out, nil
 }
+
+// ListPage returns one page of the workspace's projects, oldest first.
+func (r *Repository) ListPage(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Project], error) {
+	return common.FindPage[Project](ctx, r.col, bson.M{"workspace_id": workspaceID}, bson.D{{Key: "_id", Value: 1}}, p)
+}
+
+// FindByIdentifier returns the workspace's projects whose current or previous identifier is ident.
+func (r *Repository) FindByIdentifier(ctx context.Context, workspaceID primitive.ObjectID, ident string) ([]*Project, error) {
+	cur, err := r.col.Find(ctx, bson.M{
+		"workspace_id": workspaceID,
+		"$or":          []bson.M{{"identifier": ident}, {"previous_identifiers": ident}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Project
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWithoutIdentifier returns projects created before identifiers existed.
+func (r *Repository) ListWithoutIdentifier(ctx context.Context) ([]*Project, error) {
+	cur, err := r.col.Find(ctx, bson.M{"$or": []bson.M{{"identifier": bson.M{"$exists": false}}, {"identifier": ""}}})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Project
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
@@ -2,28 +2,63 @@ package project
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"planelite-backend/internal/common"
 )
 
+// identifierPattern: 2-10 characters, uppercase letters and digits, starting with a letter.
+var identifierPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// TaskSync is what projects need from their tasks: re-keying after an identifier change, dropping
+// estimates after a scheme change and estimate totals. Implemented by task.Service.
+type TaskSync interface {
+	RekeyProject(ctx context.Context, projectID primitive.ObjectID, identifier string) error
+	ClearEstimates(ctx context.Context, projectID primitive.ObjectID) error
+	EstimateTotals(ctx context.Context, projectID primitive.ObjectID) ([]EstimateTotal, error)
+}
+
 type Service struct {
 	repo *Repository
+	// Tasks is set after construction to break the project/task import cycle.
+	Tasks TaskSync
 }
 
 func NewService(repo *Repository) *Service {
 	return &Service{repo: repo}
 }
 
-func (s *Service) Create(ctx context.Context, workspaceID primitive.ObjectID, name string) (*Project, error) {
+// Create adds a project. An empty identifier is derived from the name; an empty scheme leaves
+// estimates off.
+func (s *Service) Create(ctx context.Context, workspaceID primitive.ObjectID, name, identifier string, scheme EstimateScheme) (*Project, error) {
 	if name == "" {
 		return nil, common.ErrInvalidInput
 	}
+	if !scheme.Valid() {
+		return nil, errUnknownScheme
+	}
+	var err error
+	if identifier == "" {
+		identifier, err = s.deriveIdentifier(ctx, workspaceID, name)
+	} else {
+		identifier = strings.ToUpper(strings.TrimSpace(identifier))
+		err = s.checkIdentifier(ctx, workspaceID, primitive.NilObjectID, identifier)
+	}
+	if err != nil {
+		return nil, err
+	}
 	p := &Project{
-		Name:        name,
-		WorkspaceID: workspaceID,
-		CreatedAt:   time.Now(),
+		Name:           name,
+		WorkspaceID:    workspaceID,
+		Identifier:     identifier,
+		EstimateScheme: scheme,
+		CreatedAt:      time.Now(),
 	}
 	if err := s.repo.Create(ctx, p); err != nil {
 		return nil, err
@@ -35,6 +70,168 @@ func (s *Service) GetByID(ctx context.Context, id primitive.ObjectID) (*Project,
 	return s.repo.FindByID(ctx, id)
 }
 
+// GetDetail returns the project with its estimate totals per status. Totals are empty while estimates
+// are off.
+func (s *Service) GetDetail(ctx context.Context, id primitive.ObjectID) (*Detail, error) {
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sum := &EstimateSummary{Scheme: p.EstimateScheme, Scale: p.EstimateScheme.Scale(), ByStatus: []EstimateTotal{}}
+	if p.EstimateScheme != EstimateNone && s.Tasks != nil {
+		totals, err := s.Tasks.EstimateTotals(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		sum.ByStatus = totals
+		for _, t := range totals {
+			sum.Total += t.Total
+		}
+	}
+	return &Detail{Project: p, Estimates: sum}, nil
+}
+
 func (s *Service) ListByWorkspace(ctx context.Context, workspaceID primitive.ObjectID) ([]*Project, error) {
 	return s.repo.ListByWorkspace(ctx, workspaceID)
 }
+
+// List returns one page of the workspace's projects.
+func (s *Service) List(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Project], error) {
+	return s.repo.ListPage(ctx, workspaceID, p)
+}
+
+// GetByIdentifier resolves a current or previous identifier within the workspace. A project's current
+// identifier wins over another project's old one.
+func (s *Service) GetByIdentifier(ctx context.Context, workspaceID primitive.ObjectID, identifier string) (*Project, error) {
+	list, err := s.repo.FindByIdentifier(ctx, workspaceID, strings.ToUpper(identifier))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, common.ErrNotFound
+	}
+	for _, p := range list {
+		if p.Identifier == strings.ToUpper(identifier) {
+			return p, nil
+		}
+	}
+	return list[0], nil
+}
+
+// Update renames the project, changes its identifier and/or its estimate scheme; empty names and
+// identifiers and a nil scheme leave a field unchanged. The old identifier is kept so existing task keys
+// still resolve. Switching schemes drops all task estimates, since values do not carry over.
+func (s *Service) Update(ctx context.Context, workspaceID, id primitive.ObjectID, name, identifier string, scheme *EstimateScheme) (*Project, error) {
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil || p.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	set := bson.M{}
+	if name != "" {
+		set["name"] = name
+	}
+	identifier = strings.ToUpper(strings.TrimSpace(identifier))
+	rekey := identifier != "" && identifier != p.Identifier
+	if rekey {
+		if err := s.checkIdentifier(ctx, workspaceID, id, identifier); err != nil {
+			return nil, err
+		}
+		prev := []string{}
+		for _, old := range p.PreviousIdentifiers {
+			if old != identifier {
+				prev = append(prev, old)
+			}
+		}
+		set["identifier"] = identifier
+		set["previous_identifiers"] = append(prev, p.Identifier)
+	}
+	rescheme := scheme != nil && *scheme != p.EstimateScheme
+	if rescheme {
+		if !scheme.Valid() {
+			return nil, errUnknownScheme
+		}
+		set["estimate_scheme"] = *scheme
+	}
+	if len(set) == 0 {
+		return p, nil
+	}
+	if err := s.repo.Update(ctx, id, set); err != nil {
+		return nil, err
+	}
+	if rekey && s.Tasks != nil {
+		if err := s.Tasks.RekeyProject(ctx, id, identifier); err != nil {
+			return nil, err
+		}
+	}
+	if rescheme && s.Tasks != nil {
+		if err := s.Tasks.ClearEstimates(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// BackfillIdentifiers gives projects created before identifiers existed one derived from their name.
+func (s *Service) BackfillIdentifiers(ctx context.Context) error {
+	list, err := s.repo.ListWithoutIdentifier(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range list {
+		ident, err := s.deriveIdentifier(ctx, p.WorkspaceID, p.Name)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, p.ID, bson.M{"identifier": ident}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIdentifier validates the format and that no other project of the workspace uses or used ident.
+func (s *Service) checkIdentifier(ctx context.Context, workspaceID, projectID primitive.ObjectID, ident string) error {
+	if !identifierPattern.MatchString(ident) {
+		return fmt.Errorf("%w: identifier must be 2-10 uppercase letters or digits, starting with a letter", common.ErrInvalidInput)
+	}
+	list, err := s.repo.FindByIdentifier(ctx, workspaceID, ident)
+	if err != nil {
+		return err
+	}
+	for _, p := range list {
+		if p.ID != projectID {
+			return fmt.Errorf("%w: identifier %s is already used in this workspace", common.ErrConflict, ident)
+		}
+	}
+	return nil
+}
+
+// deriveIdentifier takes up to the first three letters or digits of name, adding a number if taken.
+func (s *Service) deriveIdentifier(ctx context.Context, workspaceID primitive.ObjectID, name string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9' && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+		if b.Len() == 3 {
+			break
+		}
+	}
+	base := b.String()
+	for len(base) < 2 {
+		base += "P"
+	}
+	for n := 1; ; n++ {
+		ident := base
+		if n > 1 {
+			ident += strconv.Itoa(n)
+		}
+		list, err := s.repo.FindByIdentifier(ctx, workspaceID, ident)
+		if err != nil {
+			return "", err
+		}
+		if len(list) == 0 {
+			return ident, nil
+		}
+	}
+}
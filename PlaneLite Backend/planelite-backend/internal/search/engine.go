@@ -0,0 +1,13 @@
+package search
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Engine runs searches scoped to one workspace. Implementations return at most q.Limit results of
+// the kinds q asks for, best first, and must leave out trashed tasks and deleted comments.
+type Engine interface {
+	Search(ctx context.Context, workspaceID primitive.ObjectID, q Query) ([]Result, error)
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Search handles GET /workspaces/:id/search?q=&type=&limit=. A word ending in * matches as a prefix;
+// type is a comma-separated subset of task, project, comment.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	var kinds []Kind
+	for _, t := range strings.Split(query.Get("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			kinds = append(kinds, Kind(t))
+		}
+	}
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+	}
+	results, err := h.svc.Search(r.Context(), wsID, query.Get("q"), kinds, limit)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, results)
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetRadius is roughly how many bytes of context are kept on each side of the first match.
+const snippetRadius = 80
+
+// matcher returns a case-insensitive pattern matching any word that starts with a term or prefix.
+// Terms are matched as prefixes too, since the engine stems them ("run" finds "running").
+func (q Query) matcher() *regexp.Regexp {
+	words := make([]string, 0, len(q.Terms)+len(q.Prefixes))
+	for _, w := range append(append([]string{}, q.Terms...), q.Prefixes...) {
+		words = append(words, regexp.QuoteMeta(w))
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\w*`)
+}
+
+// Highlight returns an HTML-escaped excerpt of text around the first match of re, with every match
+// in the excerpt wrapped in <mark>. Without a match it returns the start of text.
+func Highlight(text string, re *regexp.Regexp) string {
+	start, end := 0, len(text)
+	first := []int(nil)
+	if re != nil {
+		first = re.FindStringIndex(text)
+	}
+	if first != nil {
+		start = max(0, first[0]-snippetRadius)
+		end = min(len(text), first[1]+snippetRadius)
+	} else {
+		end = min(len(text), 2*snippetRadius)
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	window := text[start:end]
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	last := 0
+	if re != nil {
+		for _, m := range re.FindAllStringIndex(window, -1) {
+			b.WriteString(html.EscapeString(window[last:m[0]]))
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(window[m[0]:m[1]]))
+			b.WriteString("</mark>")
+			last = m[1]
+		}
+	}
+	b.WriteString(html.EscapeString(window[last:]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kind is the type of document a result points at.
+type Kind string
+
+const (
+	KindTask    Kind = "task"
+	KindProject Kind = "project"
+	KindComment Kind = "comment"
+)
+
+// Kinds lists every searchable kind.
+var Kinds = []Kind{KindTask, KindProject, KindComment}
+
+// Query is a parsed search string. Terms match whole words (stemmed by the engine); Prefixes match
+// the start of any word, written as "auth*" in the search string.
+type Query struct {
+	Terms    []string
+	Prefixes []string
+	Kinds    []Kind
+	Limit    int
+}
+
+// ParseQuery splits a search string into terms and prefixes. Quotes and leading dashes are dropped
+// so user input cannot turn into engine operators.
+func ParseQuery(s string) Query {
+	var q Query
+	for _, f := range strings.Fields(s) {
+		prefix := strings.HasSuffix(f, "*")
+		f = strings.Trim(f, `"*-`)
+		if f == "" {
+			continue
+		}
+		if prefix {
+			q.Prefixes = append(q.Prefixes, f)
+		} else {
+			q.Terms = append(q.Terms, f)
+		}
+	}
+	return q
+}
+
+// Empty reports whether the query has nothing to search for.
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.Prefixes) == 0
+}
+
+// Wants reports whether results of kind k were asked for; no kinds means all of them.
+func (q Query) Wants(k Kind) bool {
+	if len(q.Kinds) == 0 {
+		return true
+	}
+	for _, want := range q.Kinds {
+		if want == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is one ranked match. Title is the plain task title or project name (for comments, the
+// task's title); Snippet is an HTML-escaped excerpt with matches wrapped in <mark>.
+type Result struct {
+	Kind      Kind                `json:"kind"`
+	ID        primitive.ObjectID  `json:"id"`
+	ProjectID primitive.ObjectID  `json:"project_id"`
+	TaskID    *primitive.ObjectID `json:"task_id,omitempty"`
+	TaskKey   string              `json:"task_key,omitempty"`
+	Title     string              `json:"title"`
+	Snippet   string              `json:"snippet"`
+	Score     float64             `json:"score"`
+}
@@ -0,0 +1,220 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mongo searches with the text indexes created by config.EnsureIndexes. Whole-word terms go through
+// $text and are ranked by textScore; prefixes, which $text cannot express, are matched with
+// word-start regexes. Visible matches add to the score either way.
+type Mongo struct {
+	tasks    *mongo.Collection
+	projects *mongo.Collection
+	comments *mongo.Collection
+}
+
+func NewMongo(db *mongo.Database) *Mongo {
+	return &Mongo{
+		tasks:    db.Collection("tasks"),
+		projects: db.Collection("projects"),
+		comments: db.Collection("comments"),
+	}
+}
+
+// hit is the union of the fields read from the searched collections.
+type hit struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	Title       string             `bson:"title"`
+	Description string             `bson:"description"`
+	Key         string             `bson:"key"`
+	Name        string             `bson:"name"`
+	Identifier  string             `bson:"identifier"`
+	Body        string             `bson:"body"`
+	Score       float64            `bson:"score"`
+}
+
+func (m *Mongo) Search(ctx context.Context, workspaceID primitive.ObjectID, q Query) ([]Result, error) {
+	re := q.matcher()
+	var results []Result
+
+	if q.Wants(KindProject) {
+		hits, err := m.find(ctx, m.projects, bson.M{"workspace_id": workspaceID}, q, "name", "identifier")
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hits {
+			results = append(results, Result{
+				Kind: KindProject, ID: h.ID, ProjectID: h.ID, Title: h.Name,
+				Snippet: Highlight(h.Name, re),
+				Score:   h.Score + matchScore(re, h.Name, h.Identifier),
+			})
+		}
+	}
+
+	if q.Wants(KindTask) || q.Wants(KindComment) {
+		projectIDs, err := m.projectIDs(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if q.Wants(KindTask) {
+			taskResults, err := m.searchTasks(ctx, projectIDs, q, re)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, taskResults...)
+		}
+		if q.Wants(KindComment) {
+			commentResults, err := m.searchComments(ctx, workspaceID, q, re)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, commentResults...)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+func (m *Mongo) searchTasks(ctx context.Context, projectIDs []primitive.ObjectID, q Query, re *regexp.Regexp) ([]Result, error) {
+	scope := bson.M{"project_id": bson.M{"$in": projectIDs}, "deleted_at": nil}
+	hits, err := m.find(ctx, m.tasks, scope, q, "title", "description", "key")
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, len(hits))
+	for _, h := range hits {
+		text := h.Title
+		if re != nil && !re.MatchString(h.Title) && re.MatchString(h.Description) {
+			text = h.Description
+		}
+		id := h.ID
+		results = append(results, Result{
+			Kind: KindTask, ID: h.ID, ProjectID: h.ProjectID, TaskID: &id, TaskKey: h.Key, Title: h.Title,
+			Snippet: Highlight(text, re),
+			Score:   h.Score + matchScore(re, h.Title, h.Key) + matchScore(re, h.Description)/2,
+		})
+	}
+	return results, nil
+}
+
+func (m *Mongo) searchComments(ctx context.Context, workspaceID primitive.ObjectID, q Query, re *regexp.Regexp) ([]Result, error) {
+	hits, err := m.find(ctx, m.comments, bson.M{"workspace_id": workspaceID, "deleted": false}, q, "body")
+	if err != nil || len(hits) == 0 {
+		return nil, err
+	}
+	// Comments on trashed tasks are hidden along with the task.
+	taskIDs := make([]primitive.ObjectID, 0, len(hits))
+	for _, h := range hits {
+		taskIDs = append(taskIDs, h.TaskID)
+	}
+	cur, err := m.tasks.Find(ctx, bson.M{"_id": bson.M{"$in": taskIDs}, "deleted_at": nil},
+		options.Find().SetProjection(bson.M{"title": 1, "key": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var live []hit
+	if err := cur.All(ctx, &live); err != nil {
+		return nil, err
+	}
+	tasks := make(map[primitive.ObjectID]hit, len(live))
+	for _, t := range live {
+		tasks[t.ID] = t
+	}
+
+	results := make([]Result, 0, len(hits))
+	for _, h := range hits {
+		t, ok := tasks[h.TaskID]
+		if !ok {
+			continue
+		}
+		taskID := h.TaskID
+		results = append(results, Result{
+			Kind: KindComment, ID: h.ID, ProjectID: h.ProjectID, TaskID: &taskID, TaskKey: t.Key, Title: t.Title,
+			Snippet: Highlight(h.Body, re),
+			Score:   h.Score + matchScore(re, h.Body)/2,
+		})
+	}
+	return results, nil
+}
+
+// find runs q against col within scope. Terms use the collection's text index; each prefix must
+// start a word in at least one of fields.
+func (m *Mongo) find(ctx context.Context, col *mongo.Collection, scope bson.M, q Query, fields ...string) ([]hit, error) {
+	filter := bson.M{}
+	for k, v := range scope {
+		filter[k] = v
+	}
+	opts := options.Find().SetLimit(int64(q.Limit))
+	if len(q.Terms) > 0 {
+		filter["$text"] = bson.M{"$search": strings.Join(q.Terms, " ")}
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score}).SetSort(bson.D{{Key: "score", Value: score}})
+	} else {
+		opts.SetSort(bson.D{{Key: "_id", Value: -1}})
+	}
+	if len(q.Prefixes) > 0 {
+		and := make([]bson.M, 0, len(q.Prefixes))
+		for _, p := range q.Prefixes {
+			re := primitive.Regex{Pattern: `\b` + regexp.QuoteMeta(p), Options: "i"}
+			or := make([]bson.M, 0, len(fields))
+			for _, f := range fields {
+				or = append(or, bson.M{f: re})
+			}
+			and = append(and, bson.M{"$or": or})
+		}
+		filter["$and"] = and
+	}
+
+	cur, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var hits []hit
+	if err := cur.All(ctx, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+func (m *Mongo) projectIDs(ctx context.Context, workspaceID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cur, err := m.projects.Find(ctx, bson.M{"workspace_id": workspaceID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var hits []hit
+	if err := cur.All(ctx, &hits); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids, nil
+}
+
+// matchScore counts the words in texts that match the query. It ranks prefix-only queries, which have
+// no textScore, and lifts documents that match several times.
+func matchScore(re *regexp.Regexp, texts ...string) float64 {
+	if re == nil {
+		return 0
+	}
+	n := 0
+	for _, t := range texts {
+		n += len(re.FindAllStringIndex(t, -1))
+	}
+	return float64(n)
+}
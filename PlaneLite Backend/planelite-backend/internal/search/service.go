@@ -0,0 +1,53 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+)
+
+type Service struct {
+	engine Engine
+}
+
+func NewService(engine Engine) *Service {
+	return &Service{engine: engine}
+}
+
+// Search parses text and runs it against the workspace. kinds narrows the result types (empty
+// means all); limit is clamped to 1..50 and defaults to 20.
+func (s *Service) Search(ctx context.Context, workspaceID primitive.ObjectID, text string, kinds []Kind, limit int) ([]Result, error) {
+	q := ParseQuery(text)
+	if q.Empty() {
+		return nil, fmt.Errorf("%w: q is required", common.ErrInvalidInput)
+	}
+	for _, k := range kinds {
+		if k != KindTask && k != KindProject && k != KindComment {
+			return nil, fmt.Errorf("%w: unknown type %q", common.ErrInvalidInput, k)
+		}
+	}
+	q.Kinds = kinds
+	switch {
+	case limit <= 0:
+		q.Limit = defaultLimit
+	case limit > maxLimit:
+		q.Limit = maxLimit
+	default:
+		q.Limit = limit
+	}
+	results, err := s.engine.Search(ctx, workspaceID, q)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []Result{}
+	}
+	return results, nil
+}
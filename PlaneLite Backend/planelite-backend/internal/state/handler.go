@@ -0,0 +1,297 @@
+package state
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+)
+
+type Handler struct {
+	svc      *Service
+	projects *project.Service
+}
+
+func NewHandler(svc *Service, projects *project.Service) *Handler {
+	return &Handler{svc: svc, projects: projects}
+}
+
+// CreateRequest is the JSON body for POST /workspaces/:id/projects/:pid/states.
+type CreateRequest struct {
+	Name  string `json:"name"`
+	Key   string `json:"key"` // optional; derived from name
+	Group Group  `json:"group"`
+	Color string `json:"color"`
+}
+
+// UpdateRequest is the JSON body for PATCH /workspaces/:id/projects/:pid/states/:sid.
+type UpdateRequest struct {
+	Name      *string `json:"name"`
+	Group     *Group  `json:"group"`
+	Color     *string `json:"color"`
+	Sequence  *int    `json:"sequence"`
+	IsDefault *bool   `json:"is_default"`
+}
+
+// ReorderRequest is the JSON body for POST /workspaces/:id/projects/:pid/states/reorder.
+type ReorderRequest struct {
+	StateIDs []string `json:"state_ids"`
+}
+
+// List handles GET /workspaces/:id/projects/:pid/states.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.svc.List(r.Context(), pid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, list)
+}
+
+// Create handles POST /workspaces/:id/projects/:pid/states.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	st, err := h.svc.Create(r.Context(), pid, req.Name, req.Key, req.Group, req.Color)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, st)
+}
+
+// Update handles PATCH /workspaces/:id/projects/:pid/states/:sid.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	sid, err := primitive.ObjectIDFromHex(r.PathValue("sid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	st, err := h.svc.Update(r.Context(), pid, sid, Patch{
+		Name:      req.Name,
+		Group:     req.Group,
+		Color:     req.Color,
+		Sequence:  req.Sequence,
+		IsDefault: req.IsDefault,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, st)
+}
+
+// Reorder handles POST /workspaces/:id/projects/:pid/states/reorder.
+func (h *Handler) Reorder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	var req ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	ids := make([]primitive.ObjectID, 0, len(req.StateIDs))
+	for _, hex := range req.StateIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	list, err := h.svc.Reorder(r.Context(), pid, ids)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, list)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/states/:sid.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	sid, err := primitive.ObjectIDFromHex(r.PathValue("sid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), pid, sid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// CreateTransitionRequest is the JSON body for POST /workspaces/:id/projects/:pid/transitions.
+type CreateTransitionRequest struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Roles []common.Role `json:"roles"` // optional; empty allows every role
+}
+
+// Graph handles GET /workspaces/:id/projects/:pid/transitions. Allowed moves are computed for the caller's role.
+func (h *Handler) Graph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	g, err := h.svc.Graph(r.Context(), pid, u.Role)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, g)
+}
+
+// CreateTransition handles POST /workspaces/:id/projects/:pid/transitions.
+func (h *Handler) CreateTransition(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	var req CreateTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.From == "" || req.To == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err := h.svc.CreateTransition(r.Context(), pid, req.From, req.To, req.Roles)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, t)
+}
+
+// DeleteTransition handles DELETE /workspaces/:id/projects/:pid/transitions/:trid.
+func (h *Handler) DeleteTransition(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanManageStates(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	pid, ok := h.projectPath(w, r)
+	if !ok {
+		return
+	}
+	trid, err := primitive.ObjectIDFromHex(r.PathValue("trid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.DeleteTransition(r.Context(), pid, trid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// projectPath parses the project ID from the route and checks that the project belongs to the
+// workspace, replying 400 or 404 otherwise. Checking first also keeps List from seeding states for
+// projects that do not exist.
+func (h *Handler) projectPath(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return primitive.NilObjectID, false
+	}
+	pid, err := primitive.ObjectIDFromHex(r.PathValue("pid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return primitive.NilObjectID, false
+	}
+	p, err := h.projects.GetByID(r.Context(), pid)
+	if err != nil || p.WorkspaceID != wsID {
+		common.Error(w, common.ErrNotFound)
+		return primitive.NilObjectID, false
+	}
+	return pid, true
+}
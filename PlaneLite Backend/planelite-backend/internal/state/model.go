@@ -0,0 +1,53 @@
+package state
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Group is the category a workflow state belongs to; it drives reporting and "done" semantics.
+type Group string
+
+const (
+	GroupUnstarted Group = "unstarted"
+	GroupStarted   Group = "started"
+	GroupCompleted Group = "completed"
+	GroupCancelled Group = "cancelled"
+)
+
+// Valid reports whether g is one of the known groups.
+func (g Group) Valid() bool {
+	return g == GroupUnstarted || g == GroupStarted || g == GroupCompleted || g == GroupCancelled
+}
+
+// Closed reports whether tasks in this group are finished (completed or cancelled).
+func (g Group) Closed() bool {
+	return g == GroupCompleted || g == GroupCancelled
+}
+
+// State is a per-project workflow state. Tasks store the state Key as their status.
+type State struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
+
+	// Key is the stable, upper-case identifier stored on tasks (e.g. IN_REVIEW). Immutable.
+	Key   string `bson:"key" json:"key"`
+	Name  string `bson:"name" json:"name"`
+	Group Group  `bson:"group" json:"group"`
+	Color string `bson:"color" json:"color"`
+
+	Sequence  int  `bson:"sequence" json:"sequence"`
+	IsDefault bool `bson:"is_default" json:"is_default"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// defaultStates are seeded for projects without states. Keys match the original fixed task statuses
+// (TODO, IN_PROGRESS, DONE) so tasks created before custom states stay valid.
+var defaultStates = []State{
+	{Key: "TODO", Name: "Todo", Group: GroupUnstarted, Color: "#A3A3A3", Sequence: 1, IsDefault: true},
+	{Key: "IN_PROGRESS", Name: "In Progress", Group: GroupStarted, Color: "#F59E0B", Sequence: 2},
+	{Key: "DONE", Name: "Done", Group: GroupCompleted, Color: "#16A34A", Sequence: 3},
+}
@@ -0,0 +1,10 @@
+package state
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanManageStates: only PROJECT_MANAGER (and ADMIN) can change a project's workflow.
+func CanManageStates(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	col *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("states")}
+}
+
+func (r *Repository) Create(ctx context.Context, s *State) error {
+	doc := bson.M{
+		"project_id": s.ProjectID,
+		"key":        s.Key,
+		"name":       s.Name,
+		"group":      s.Group,
+		"color":      s.Color,
+		"sequence":   s.Sequence,
+		"is_default": s.IsDefault,
+		"created_at": s.CreatedAt,
+	}
+	result, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		s.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*State, error) {
+	var s State
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *Repository) FindByKey(ctx context.Context, projectID primitive.ObjectID, key string) (*State, error) {
+	var s State
+	err := r.col.FindOne(ctx, bson.M{"project_id": projectID, "key": key}).Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByProject returns states ordered by sequence.
+func (r *Repository) ListByProject(ctx context.Context, projectID primitive.ObjectID) ([]*State, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*State
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	return err
+}
+
+// ClearDefault unsets is_default on every state of the project.
+func (r *Repository) ClearDefault(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"project_id": projectID, "is_default": true}, bson.M{"$set": bson.M{"is_default": false}})
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,384 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
+)
+
+// TaskCounter reports how many tasks of a project use a state key. Implemented by task.Service;
+// set on Service after construction to avoid an import cycle.
+type TaskCounter interface {
+	CountByStatus(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error)
+}
+
+type Service struct {
+	repo      *Repository
+	transRepo *TransitionRepository
+	// Tasks guards deletion of states still referenced by tasks. Optional.
+	Tasks TaskCounter
+}
+
+func NewService(repo *Repository, transRepo *TransitionRepository) *Service {
+	return &Service{repo: repo, transRepo: transRepo}
+}
+
+// Patch holds optional state fields for Update; nil means unchanged.
+type Patch struct {
+	Name      *string
+	Group     *Group
+	Color     *string
+	Sequence  *int
+	IsDefault *bool
+}
+
+// List returns the project's states ordered by sequence. Projects without states are seeded
+// with the defaults first, which is how existing projects migrate onto configurable states.
+func (s *Service) List(ctx context.Context, projectID primitive.ObjectID) ([]*State, error) {
+	list, err := s.repo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list, nil
+	}
+	if err := s.seedDefaults(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+// Get returns the project's state with the given key, or ErrInvalidInput if the key is unknown.
+func (s *Service) Get(ctx context.Context, projectID primitive.ObjectID, key string) (*State, error) {
+	list, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range list {
+		if st.Key == key {
+			return st, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown status %q for this project", common.ErrInvalidInput, key)
+}
+
+// Default returns the state new tasks start in.
+func (s *Service) Default(ctx context.Context, projectID primitive.ObjectID) (*State, error) {
+	list, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range list {
+		if st.IsDefault {
+			return st, nil
+		}
+	}
+	return list[0], nil
+}
+
+// ClosedKeys returns the keys of the project's completed and cancelled states.
+func (s *Service) ClosedKeys(ctx context.Context, projectID primitive.ObjectID) ([]string, error) {
+	list, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for _, st := range list {
+		if st.Group.Closed() {
+			keys = append(keys, st.Key)
+		}
+	}
+	return keys, nil
+}
+
+// Create adds a state at the end of the project's ordering. Key defaults to the name upper-cased.
+func (s *Service) Create(ctx context.Context, projectID primitive.ObjectID, name, key string, group Group, color string) (*State, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || !group.Valid() {
+		return nil, common.ErrInvalidInput
+	}
+	if key == "" {
+		key = name
+	}
+	key = normalizeKey(key)
+	if key == "" {
+		return nil, common.ErrInvalidInput
+	}
+	list, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	seq := 1
+	for _, st := range list {
+		if st.Key == key {
+			return nil, common.ErrConflict
+		}
+		if st.Sequence >= seq {
+			seq = st.Sequence + 1
+		}
+	}
+	st := &State{
+		ProjectID: projectID,
+		Key:       key,
+		Name:      name,
+		Group:     group,
+		Color:     color,
+		Sequence:  seq,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, st); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, common.ErrConflict
+		}
+		return nil, err
+	}
+	return st, nil
+}
+
+// Update changes name, group, color, sequence or default flag. The key is immutable.
+func (s *Service) Update(ctx context.Context, projectID, id primitive.ObjectID, p Patch) (*State, error) {
+	st, err := s.repo.FindByID(ctx, id)
+	if err != nil || st.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	up := bson.M{}
+	if p.Name != nil {
+		name := strings.TrimSpace(*p.Name)
+		if name == "" {
+			return nil, common.ErrInvalidInput
+		}
+		up["name"] = name
+	}
+	if p.Group != nil {
+		if !p.Group.Valid() {
+			return nil, common.ErrInvalidInput
+		}
+		up["group"] = *p.Group
+	}
+	if p.Color != nil {
+		up["color"] = *p.Color
+	}
+	if p.Sequence != nil {
+		up["sequence"] = *p.Sequence
+	}
+	if p.IsDefault != nil {
+		if !*p.IsDefault && st.IsDefault {
+			return nil, fmt.Errorf("%w: mark another state as default instead", common.ErrInvalidInput)
+		}
+		if *p.IsDefault && !st.IsDefault {
+			if err := s.repo.ClearDefault(ctx, projectID); err != nil {
+				return nil, err
+			}
+			up["is_default"] = true
+		}
+	}
+	if len(up) > 0 {
+		if err := s.repo.Update(ctx, id, up); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Reorder assigns sequences following the given order of state IDs.
+func (s *Service) Reorder(ctx context.Context, projectID primitive.ObjectID, ids []primitive.ObjectID) ([]*State, error) {
+	list, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(list) {
+		return nil, fmt.Errorf("%w: order must list every state of the project", common.ErrInvalidInput)
+	}
+	known := make(map[primitive.ObjectID]bool, len(list))
+	for _, st := range list {
+		known[st.ID] = true
+	}
+	for i, id := range ids {
+		if !known[id] {
+			return nil, common.ErrInvalidInput
+		}
+		delete(known, id)
+		if err := s.repo.Update(ctx, id, bson.M{"sequence": i + 1}); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+// Delete removes a state. The default state and states still used by tasks cannot be deleted.
+func (s *Service) Delete(ctx context.Context, projectID, id primitive.ObjectID) error {
+	st, err := s.repo.FindByID(ctx, id)
+	if err != nil || st.ProjectID != projectID {
+		return common.ErrNotFound
+	}
+	if st.IsDefault {
+		return fmt.Errorf("%w: cannot delete the default state", common.ErrInvalidInput)
+	}
+	if s.Tasks != nil {
+		n, err := s.Tasks.CountByStatus(ctx, projectID, st.Key)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: state %s is used by %d task(s)", common.ErrConflict, st.Key, n)
+		}
+	}
+	if err := s.transRepo.DeleteByKey(ctx, projectID, st.Key); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// CreateTransition allows moving tasks from one state to another, optionally only for some roles.
+func (s *Service) CreateTransition(ctx context.Context, projectID primitive.ObjectID, from, to string, roles []common.Role) (*Transition, error) {
+	if from == to {
+		return nil, common.ErrInvalidInput
+	}
+	if _, err := s.Get(ctx, projectID, from); err != nil {
+		return nil, err
+	}
+	if _, err := s.Get(ctx, projectID, to); err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		if r != common.RoleAdmin && r != common.RoleProjectManager && r != common.RoleUser {
+			return nil, fmt.Errorf("%w: unknown role %q", common.ErrInvalidInput, r)
+		}
+	}
+	if roles == nil {
+		roles = []common.Role{}
+	}
+	t := &Transition{
+		ProjectID: projectID,
+		From:      from,
+		To:        to,
+		Roles:     roles,
+		CreatedAt: time.Now(),
+	}
+	if err := s.transRepo.Create(ctx, t); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, common.ErrConflict
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteTransition removes a transition rule.
+func (s *Service) DeleteTransition(ctx context.Context, projectID, id primitive.ObjectID) error {
+	t, err := s.transRepo.FindByID(ctx, id)
+	if err != nil || t.ProjectID != projectID {
+		return common.ErrNotFound
+	}
+	return s.transRepo.Delete(ctx, id)
+}
+
+// CheckTransition returns nil if role may move a task of the project from one state to another.
+// Illegal moves return ErrInvalidInput; moves the role may not perform return ErrForbidden.
+func (s *Service) CheckTransition(ctx context.Context, projectID primitive.ObjectID, from, to string, role common.Role) error {
+	if from == to {
+		return nil
+	}
+	list, err := s.transRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	for _, t := range list {
+		if t.From != from || t.To != to {
+			continue
+		}
+		if !t.Allows(role) {
+			return fmt.Errorf("%w: role %s may not move tasks from %s to %s", common.ErrForbidden, role, from, to)
+		}
+		return nil
+	}
+	var targets []string
+	for _, t := range list {
+		if t.From == from {
+			targets = append(targets, t.To)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: transition %s -> %s is not allowed; %s has no outgoing transitions", common.ErrInvalidInput, from, to, from)
+	}
+	return fmt.Errorf("%w: transition %s -> %s is not allowed; from %s a task can move to %s",
+		common.ErrInvalidInput, from, to, from, strings.Join(targets, ", "))
+}
+
+// Graph returns the project's states and transitions, plus the moves available to role.
+func (s *Service) Graph(ctx context.Context, projectID primitive.ObjectID, role common.Role) (*Graph, error) {
+	states, err := s.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	trans, err := s.transRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if trans == nil {
+		trans = []*Transition{}
+	}
+	g := &Graph{
+		States:      states,
+		Transitions: trans,
+		Restricted:  len(trans) > 0,
+		Allowed:     make(map[string][]string, len(states)),
+	}
+	for _, from := range states {
+		targets := []string{}
+		for _, to := range states {
+			if to.Key == from.Key {
+				continue
+			}
+			if !g.Restricted {
+				targets = append(targets, to.Key)
+				continue
+			}
+			for _, t := range trans {
+				if t.From == from.Key && t.To == to.Key && t.Allows(role) {
+					targets = append(targets, to.Key)
+					break
+				}
+			}
+		}
+		g.Allowed[from.Key] = targets
+	}
+	return g, nil
+}
+
+func (s *Service) seedDefaults(ctx context.Context, projectID primitive.ObjectID) error {
+	now := time.Now()
+	for _, d := range defaultStates {
+		st := d
+		st.ProjectID = projectID
+		st.CreatedAt = now
+		// A concurrent request may have seeded the same project; the unique index makes that harmless.
+		if err := s.repo.Create(ctx, &st); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeKey upper-cases key and replaces anything but letters and digits with underscores.
+func normalizeKey(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(strings.TrimSpace(key)) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+// Transition allows moving tasks from one state key to another. A project without transitions
+// has an open workflow; once any exist, only listed moves are legal.
+type Transition struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
+
+	From string `bson:"from" json:"from"`
+	To   string `bson:"to" json:"to"`
+
+	// Roles that may perform the move; empty means any role.
+	Roles []common.Role `bson:"roles" json:"roles"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Allows reports whether role may perform this transition.
+func (t *Transition) Allows(role common.Role) bool {
+	if len(t.Roles) == 0 {
+		return true
+	}
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Graph is the workflow of a project as exposed to clients.
+type Graph struct {
+	States      []*State      `json:"states"`
+	Transitions []*Transition `json:"transitions"`
+	// Restricted is false when the project has no transitions and every move is allowed.
+	Restricted bool `json:"restricted"`
+	// Allowed maps each state key to the keys the requesting role may move a task to.
+	Allowed map[string][]string `json:"allowed"`
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TransitionRepository handles the transitions collection.
+type TransitionRepository struct {
+	col *mongo.Collection
+}
+
+func NewTransitionRepository(db *mongo.Database) *TransitionRepository {
+	return &TransitionRepository{col: db.Collection("transitions")}
+}
+
+func (r *TransitionRepository) Create(ctx context.Context, t *Transition) error {
+	doc := bson.M{
+		"project_id": t.ProjectID,
+		"from":       t.From,
+		"to":         t.To,
+		"roles":      t.Roles,
+		"created_at": t.CreatedAt,
+	}
+	result, err := r.col.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		t.ID = oid
+	}
+	return nil
+}
+
+func (r *TransitionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*Transition, error) {
+	var t Transition
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TransitionRepository) ListByProject(ctx context.Context, projectID primitive.ObjectID) ([]*Transition, error) {
+	cur, err := r.col.Find(ctx, bson.M{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Transition
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *TransitionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// DeleteByKey removes every transition of the project that starts or ends at key.
+func (r *TransitionRepository) DeleteByKey(ctx context.Context, projectID primitive.ObjectID, key string) error {
+	_, err := r.col.DeleteMany(ctx, bson.M{
+		"project_id": projectID,
+		"$or":        []bson.M{{"from": key}, {"to": key}},
+	})
+	return err
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"planelite-backend/internal/config"
+)
+
+// Blob stores opaque objects by key. Keys are slash-separated and chosen by the caller.
+// Get returns common.ErrNotFound for missing keys; Delete of a missing key is not an error.
+type Blob interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the blob store selected by cfg.StorageBackend.
+func New(cfg *config.Config) (Blob, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		return NewLocal(cfg.StorageLocalDir)
+	case "s3":
+		return &S3{
+			Endpoint:  strings.TrimRight(cfg.S3Endpoint, "/"),
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Client:    http.DefaultClient,
+		}, nil
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.StorageBackend)
+	}
+}
+
+// validKey rejects keys that could escape the store's root.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package storage
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"attachments/abc/report.pdf": true,
+		"a":                          true,
+		"":                           false,
+		"/etc/passwd":                false,
+		"../secret":                  false,
+		"a/../../b":                  false,
+		"a/./b":                      false,
+		"a//b":                       false,
+		"a/":                         false,
+		`a\..\b`:                     false,
+		`a\b`:                        false,
+	} {
+		if got := validKey(key); got != want {
+			t.Errorf("validKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
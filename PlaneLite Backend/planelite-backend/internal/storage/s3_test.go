@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"planelite-backend/internal/common"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+)
+
+// fakeS3 is an in-memory stand-in for an S3 bucket that rejects requests whose SigV4 signature does
+// not verify.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verify(r); err != "" {
+		f.t.Errorf("%s %s: %s", r.Method, r.URL.Path, err)
+		http.Error(w, err, http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		if _, ok := f.objects[r.URL.Path]; !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verify recomputes the SigV4 signature from the request as received and compares it with the
+// Authorization header.
+func (f *fakeS3) verify(r *http.Request) string {
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) != len("20060102T150405Z") {
+		return "missing X-Amz-Date"
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		return "missing X-Amz-Content-Sha256"
+	}
+	scope := amzDate[:8] + "/" + testRegion + "/s3/aws4_request"
+	canonical := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:UNSIGNED-PAYLOAD\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		"UNSIGNED-PAYLOAD"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{amzDate[:8], testRegion, "s3", "aws4_request", toSign} {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(part))
+		key = h.Sum(nil)
+	}
+	want := "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(key)
+	if got := r.Header.Get("Authorization"); got != want {
+		return "Authorization = " + got + ", want " + want
+	}
+	return ""
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	fake := &fakeS3{t: t, objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return &S3{
+		Endpoint:  srv.URL,
+		Region:    testRegion,
+		Bucket:    "planelite",
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		Client:    srv.Client(),
+	}, fake
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, fake := newTestS3(t)
+	const key, body = "attachments/t1/my report+v2.pdf", "%PDF-1.7"
+	if err := s.Put(ctx, key, strings.NewReader(body), int64(len(body)), "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	fake.mu.Lock()
+	_, ok := fake.objects["/planelite/"+key]
+	fake.mu.Unlock()
+	if !ok {
+		t.Fatal("object not stored under the bucket path")
+	}
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(got) != body {
+		t.Fatalf("Get = %q, %v; want %q", got, err, body)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+}
+
+func TestS3NotFound(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestS3(t)
+	if _, err := s.Get(ctx, "attachments/missing"); !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Get: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "attachments/missing"); err != nil {
+		t.Errorf("Delete of missing key: %v", err)
+	}
+}
+
+func TestS3RejectsInvalidKey(t *testing.T) {
+	s, _ := newTestS3(t)
+	if _, err := s.Get(context.Background(), "../other-bucket/key"); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("Get: err = %v, want ErrInvalidInput", err)
+	}
+}
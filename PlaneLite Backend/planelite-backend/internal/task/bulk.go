@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/activity"
+	"planelite-backend/internal/common"
+)
+
+// MaxBulkTasks caps how many tasks one bulk request may touch.
+const MaxBulkTasks = 100
+
+// BulkChange is applied to every task of a bulk request; zero fields leave a task alone.
+type BulkChange struct {
+	Status          TaskStatus
+	Priority        TaskPriority
+	AddAssignees    []primitive.ObjectID
+	RemoveAssignees []primitive.ObjectID
+	AddLabels       []primitive.ObjectID
+	RemoveLabels    []primitive.ObjectID
+	MoveTo          *primitive.ObjectID // target project; sub-tasks stay behind, as with Move
+	Archive         *bool               // true archives, false unarchives
+	Delete          bool                // moves the task to the trash; other changes are ignored
+	// OverrideBlockers allows starting or completing tasks whose blockers are still open.
+	OverrideBlockers bool
+}
+
+func (c BulkChange) empty() bool {
+	return c.Status == "" && c.Priority == "" && len(c.AddAssignees) == 0 && len(c.RemoveAssignees) == 0 &&
+		len(c.AddLabels) == 0 && len(c.RemoveLabels) == 0 && c.MoveTo == nil && c.Archive == nil && !c.Delete
+}
+
+// BulkResult is the outcome for one task. Changes lists what was applied, which on failure is what
+// happened before the error.
+type BulkResult struct {
+	TaskID  string   `json:"task_id"`
+	OK      bool     `json:"ok"`
+	Status  int      `json:"status"`
+	Error   string   `json:"error,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+}
+
+// BulkReport is the response of a bulk request.
+type BulkReport struct {
+	Results   []BulkResult `json:"results"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+}
+
+// Bulk applies ch to each task of the project, one task at a time. Permissions are checked per task
+// the same way as the single-task endpoints, so one request can partly succeed. Each task that changed
+// gets one activity entry.
+func (s *Service) Bulk(ctx context.Context, workspaceID, projectID primitive.ObjectID, actor Actor, ids []primitive.ObjectID, ch BulkChange) (*BulkReport, error) {
+	if len(ids) == 0 || len(ids) > MaxBulkTasks {
+		return nil, fmt.Errorf("%w: task_ids must list 1 to %d tasks", common.ErrInvalidInput, MaxBulkTasks)
+	}
+	if ch.empty() {
+		return nil, fmt.Errorf("%w: no changes given", common.ErrInvalidInput)
+	}
+	if ch.Priority != "" && !validPriority(ch.Priority) {
+		return nil, fmt.Errorf("%w: unknown priority %q", common.ErrInvalidInput, ch.Priority)
+	}
+	if err := s.checkProject(ctx, workspaceID, projectID); err != nil {
+		return nil, err
+	}
+
+	report := &BulkReport{Results: make([]BulkResult, 0, len(ids))}
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		changes, err := s.bulkOne(ctx, workspaceID, projectID, actor, id, ch)
+		if len(changes) > 0 {
+			s.record(ctx, workspaceID, projectID, id, actor.UserID, activity.KindTaskUpdated, map[string]any{
+				"bulk":    true,
+				"changes": changes,
+			})
+		}
+		res := BulkResult{TaskID: id.Hex(), OK: err == nil, Status: common.StatusFromError(err), Changes: changes}
+		if err != nil {
+			res.Error = err.Error()
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// bulkOne checks every permission ch needs on the task before changing anything, then applies the
+// changes in order and stops at the first failure.
+func (s *Service) bulkOne(ctx context.Context, workspaceID, projectID primitive.ObjectID, actor Actor, id primitive.ObjectID, ch BulkChange) ([]string, error) {
+	t, err := s.findLive(ctx, id)
+	if err != nil || t.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	if (ch.Status != "" || ch.Priority != "") && !CanUpdateTaskStatusOrPriority(actor.Role, t.IsAssignee(actor.UserID)) {
+		return nil, common.ErrForbidden
+	}
+	if (len(ch.AddAssignees) > 0 || len(ch.RemoveAssignees) > 0) && !CanAssignTask(actor.Role) {
+		return nil, common.ErrForbidden
+	}
+	if (len(ch.AddLabels) > 0 || len(ch.RemoveLabels) > 0 || ch.MoveTo != nil || ch.Archive != nil || ch.Delete) && !CanUpdateTaskFull(actor.Role) {
+		return nil, common.ErrForbidden
+	}
+
+	if ch.Delete {
+		if err := s.Delete(ctx, workspaceID, projectID, id, actor.UserID); err != nil {
+			return nil, err
+		}
+		return []string{"deleted"}, nil
+	}
+
+	var changes []string
+	if ch.Status != "" && ch.Status != t.Status {
+		if _, err := s.UpdateStatus(ctx, actor, id, common.AnyVersion, ch.Status, ch.OverrideBlockers); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "status")
+	}
+	if ch.Priority != "" && ch.Priority != t.Priority {
+		if _, err := s.UpdatePriority(ctx, id, common.AnyVersion, ch.Priority); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "priority")
+	}
+	for _, u := range ch.AddAssignees {
+		if err := s.Assign(ctx, workspaceID, projectID, id, u); err != nil {
+			return changes, err
+		}
+	}
+	for _, u := range ch.RemoveAssignees {
+		if err := s.Unassign(ctx, workspaceID, projectID, id, u); err != nil {
+			return changes, err
+		}
+	}
+	if len(ch.AddAssignees) > 0 || len(ch.RemoveAssignees) > 0 {
+		changes = append(changes, "assignees")
+	}
+	for _, l := range ch.AddLabels {
+		if err := s.AddLabel(ctx, workspaceID, projectID, id, l); err != nil {
+			return changes, err
+		}
+	}
+	for _, l := range ch.RemoveLabels {
+		if err := s.RemoveLabel(ctx, workspaceID, projectID, id, l); err != nil {
+			return changes, err
+		}
+	}
+	if len(ch.AddLabels) > 0 || len(ch.RemoveLabels) > 0 {
+		changes = append(changes, "labels")
+	}
+	if ch.Archive != nil {
+		archive, change := s.Unarchive, "unarchived"
+		if *ch.Archive {
+			archive, change = s.Archive, "archived"
+		}
+		if err := archive(ctx, workspaceID, projectID, id, actor.UserID); err != nil {
+			return changes, err
+		}
+		changes = append(changes, change)
+	}
+	if ch.MoveTo != nil && *ch.MoveTo != projectID {
+		if _, err := s.move(ctx, workspaceID, id, *ch.MoveTo, MoveOptions{Labels: true}); err != nil {
+			return changes, err
+		}
+		changes = append(changes, "project")
+	}
+	return changes, nil
+}
+
+// record writes an activity entry; failures are not surfaced since the task change already succeeded.
+func (s *Service) record(ctx context.Context, workspaceID, projectID, taskID, userID primitive.ObjectID, kind activity.ActivityKind, payload map[string]any) {
+	if s.activity == nil {
+		return
+	}
+	_ = s.activity.Record(ctx, workspaceID, projectID, taskID, userID, kind, payload)
+}
@@ -1,9 +1,14 @@
 package task
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"planelite-backend/internal/common"
@@ -18,15 +23,40 @@ func NewHandler(svc *Service) *Handler {
 }
 
 type CreateRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"` // optional, RFC 3339
+	DueDate     *time.Time `json:"due_date"`   // optional, RFC 3339
+	ParentID    string     `json:"parent_id"`  // optional; creates a sub-task
+	// Estimate is optional: a label of the project's scale or, for hours, a number.
+	Estimate *EstimateInput `json:"estimate"`
 }
 
+// UpdateRequest is the body of PUT .../tasks/:tid, which replaces every editable field: title, status
+// and priority are required, and an omitted description, date or estimate is cleared.
 type UpdateRequest struct {
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Status      TaskStatus   `json:"status"`
-	Priority    TaskPriority `json:"priority"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Status      TaskStatus     `json:"status"`
+	Priority    TaskPriority   `json:"priority"`
+	StartDate   *time.Time     `json:"start_date"`
+	DueDate     *time.Time     `json:"due_date"`
+	Estimate    *EstimateInput `json:"estimate"`
+	// OverrideBlockers allows starting or completing a task whose blockers are still open.
+	OverrideBlockers bool `json:"override_blockers"`
+}
+
+// PatchRequest is the JSON Merge Patch body of PATCH .../tasks/:tid: absent members are left alone and
+// null clears the description, a date or the estimate (title, status and priority cannot be null).
+type PatchRequest struct {
+	Title            common.Optional[string]        `json:"title"`
+	Description      common.Optional[string]        `json:"description"`
+	Status           common.Optional[TaskStatus]    `json:"status"`
+	Priority         common.Optional[TaskPriority]  `json:"priority"`
+	StartDate        common.Optional[time.Time]     `json:"start_date"`
+	DueDate          common.Optional[time.Time]     `json:"due_date"`
+	Estimate         common.Optional[EstimateInput] `json:"estimate"`
+	OverrideBlockers bool                           `json:"override_blockers"`
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -58,7 +88,22 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	t, err := h.svc.Create(r.Context(), projectID, createdBy, req.Title, req.Description)
+	in := CreateInput{
+		Title:       req.Title,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		DueDate:     req.DueDate,
+		Estimate:    req.Estimate,
+	}
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		in.ParentID = &parentID
+	}
+	t, err := h.svc.Create(r.Context(), projectID, createdBy, in)
 	if err != nil {
 		common.Error(w, err)
 		return
@@ -81,74 +126,249 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	t, err := h.svc.GetByID(r.Context(), id)
+	t, err := h.svc.GetDetail(r.Context(), id)
 	if err != nil {
 		common.Error(w, common.ErrNotFound)
 		return
 	}
+	common.SetETag(w, t.Version)
 	common.OK(w, t)
 }
 
-func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+// ListChildren handles GET /workspaces/:id/projects/:pid/tasks/:tid/children.
+func (h *Handler) ListChildren(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	list, err := h.svc.ListChildren(r.Context(), wsID, pid, tid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, list)
+}
+
+// SetParentRequest is the JSON body for PUT .../tasks/:tid/parent. An empty parent_id makes the task top-level.
+type SetParentRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+// SetParent handles PUT /workspaces/:id/projects/:pid/tasks/:tid/parent.
+func (h *Handler) SetParent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
 	u := common.GetContextUser(r.Context())
-	if u == nil || !CanUpdateTaskStatusOrPriority(u.Role) {
+	if u == nil || !CanUpdateTaskFull(u.Role) {
 		common.Error(w, common.ErrForbidden)
 		return
 	}
-	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	version, err := common.IfMatch(r)
 	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	var req SetParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	var req struct {
-		Status TaskStatus `json:"status"`
+	var parentID *primitive.ObjectID
+	if req.ParentID != "" {
+		id, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		parentID = &id
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Status == "" {
+	t, err := h.svc.SetParent(r.Context(), wsID, pid, tid, version, parentID)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	common.SetETag(w, t.Version)
+	common.NoContent(w)
+}
+
+// GetByKey handles GET /workspaces/:id/tasks/:key, e.g. /workspaces/:id/tasks/WEB-123.
+func (h *Handler) GetByKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	if err := h.svc.UpdateStatus(r.Context(), tid, req.Status); err != nil {
+	d, err := h.svc.GetByKey(r.Context(), wsID, r.PathValue("key"))
+	if err != nil {
 		common.Error(w, err)
 		return
 	}
-	common.NoContent(w)
+	common.SetETag(w, d.Version)
+	common.OK(w, d)
 }
 
-func (h *Handler) UpdatePriority(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+// Delete handles DELETE /workspaces/:id/projects/:pid/tasks/:tid. The task moves to the trash.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.lifecycle(w, r, http.MethodDelete, func(ctx context.Context, userID, wsID, pid, tid primitive.ObjectID) error {
+		return h.svc.Delete(ctx, wsID, pid, tid, userID)
+	})
+}
+
+// Restore handles POST /workspaces/:id/projects/:pid/tasks/:tid/restore (takes the task out of the trash).
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.lifecycle(w, r, http.MethodPost, func(ctx context.Context, userID, wsID, pid, tid primitive.ObjectID) error {
+		return h.svc.Restore(ctx, wsID, pid, tid, userID)
+	})
+}
+
+// Archive handles POST /workspaces/:id/projects/:pid/tasks/:tid/archive.
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.lifecycle(w, r, http.MethodPost, func(ctx context.Context, userID, wsID, pid, tid primitive.ObjectID) error {
+		return h.svc.Archive(ctx, wsID, pid, tid, userID)
+	})
+}
+
+// Unarchive handles POST /workspaces/:id/projects/:pid/tasks/:tid/unarchive.
+func (h *Handler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.lifecycle(w, r, http.MethodPost, func(ctx context.Context, userID, wsID, pid, tid primitive.ObjectID) error {
+		return h.svc.Unarchive(ctx, wsID, pid, tid, userID)
+	})
+}
+
+// lifecycle runs a trash/archive operation for PROJECT_MANAGER or ADMIN callers and replies 204.
+func (h *Handler) lifecycle(w http.ResponseWriter, r *http.Request, method string, fn func(ctx context.Context, userID, wsID, pid, tid primitive.ObjectID) error) {
+	if r.Method != method {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
 	u := common.GetContextUser(r.Context())
-	if u == nil || !CanUpdateTaskStatusOrPriority(u.Role) {
+	if u == nil || !CanUpdateTaskFull(u.Role) {
 		common.Error(w, common.ErrForbidden)
 		return
 	}
-	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
 	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	var req struct {
-		Priority TaskPriority `json:"priority"`
+	if err := fn(r.Context(), userID, wsID, pid, tid); err != nil {
+		common.Error(w, err)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Priority == "" {
+	common.NoContent(w)
+}
+
+// ListTrash handles GET /workspaces/:id/projects/:pid/tasks/trash (paged like other task lists).
+func (h *Handler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	pid, err := primitive.ObjectIDFromHex(r.PathValue("pid"))
+	if err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	if err := h.svc.UpdatePriority(r.Context(), tid, req.Priority); err != nil {
+	p, err := common.ParseListParams(r)
+	if err != nil {
 		common.Error(w, err)
 		return
 	}
-	common.NoContent(w)
+	page, err := h.svc.ListTrash(r.Context(), wsID, pid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
 }
 
-func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+type MoveRequest struct {
+	ProjectID       string `json:"project_id"`
+	IncludeSubtasks bool   `json:"include_subtasks"`
+	IncludeLabels   *bool  `json:"include_labels"` // default true
+}
+
+// Move handles POST /workspaces/:id/projects/:pid/tasks/:tid/move (PROJECT_MANAGER/ADMIN).
+func (h *Handler) Move(w http.ResponseWriter, r *http.Request) {
+	var req MoveRequest
+	actor, wsID, tid, target, ok := h.relocation(w, r, &req, func() string { return req.ProjectID })
+	if !ok {
+		return
+	}
+	t, err := h.svc.Move(r.Context(), wsID, actor, tid, target, MoveOptions{
+		Subtasks: req.IncludeSubtasks,
+		Labels:   req.IncludeLabels == nil || *req.IncludeLabels,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, t)
+}
+
+type CopyRequest struct {
+	ProjectID          string `json:"project_id"` // defaults to the task's project
+	IncludeSubtasks    bool   `json:"include_subtasks"`
+	IncludeComments    bool   `json:"include_comments"`
+	IncludeAttachments bool   `json:"include_attachments"`
+	IncludeLabels      *bool  `json:"include_labels"` // default true
+}
+
+// Copy handles POST /workspaces/:id/projects/:pid/tasks/:tid/copy (PROJECT_MANAGER/ADMIN).
+func (h *Handler) Copy(w http.ResponseWriter, r *http.Request) {
+	var req CopyRequest
+	actor, wsID, tid, target, ok := h.relocation(w, r, &req, func() string {
+		if req.ProjectID == "" {
+			return r.PathValue("pid")
+		}
+		return req.ProjectID
+	})
+	if !ok {
+		return
+	}
+	t, err := h.svc.Copy(r.Context(), wsID, actor, tid, target, CopyOptions{
+		Subtasks:    req.IncludeSubtasks,
+		Comments:    req.IncludeComments,
+		Attachments: req.IncludeAttachments,
+		Labels:      req.IncludeLabels == nil || *req.IncludeLabels,
+	})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, t)
+}
+
+// relocation parses a move or copy request into req for PROJECT_MANAGER or ADMIN callers. The task must
+// be in the project of the path; target reads the destination project from the decoded request.
+func (h *Handler) relocation(w http.ResponseWriter, r *http.Request, req any, target func() string) (actor Actor, wsID, tid, targetID primitive.ObjectID, ok bool) {
+	if r.Method != http.MethodPost {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
@@ -157,25 +377,75 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrForbidden)
 		return
 	}
-	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, err = primitive.ObjectIDFromHex(r.PathValue("id"))
 	if err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	var req UpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	pid, err := primitive.ObjectIDFromHex(r.PathValue("pid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	tid, err = primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	targetID, err = primitive.ObjectIDFromHex(target())
+	if err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	if err := h.svc.Update(r.Context(), tid, req.Title, req.Description, req.Status, req.Priority); err != nil {
+	if err := h.svc.CheckInProject(r.Context(), pid, tid); err != nil {
 		common.Error(w, err)
 		return
 	}
-	common.NoContent(w)
+	return Actor{UserID: userID, Role: u.Role}, wsID, tid, targetID, true
 }
 
-func (h *Handler) ListByProject(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+type BulkRequest struct {
+	TaskIDs           []string     `json:"task_ids"`
+	Status            TaskStatus   `json:"status"`
+	Priority          TaskPriority `json:"priority"`
+	AddAssigneeIDs    []string     `json:"add_assignee_ids"`
+	RemoveAssigneeIDs []string     `json:"remove_assignee_ids"`
+	AddLabelIDs       []string     `json:"add_label_ids"`
+	RemoveLabelIDs    []string     `json:"remove_label_ids"`
+	ProjectID         string       `json:"project_id"` // moves the tasks to this project
+	Archive           *bool        `json:"archive"`    // true archives, false unarchives
+	Delete            bool         `json:"delete"`     // moves the tasks to the trash
+	OverrideBlockers  bool         `json:"override_blockers"`
+}
+
+// Bulk handles POST /workspaces/:id/projects/:pid/tasks/bulk. It replies 200 with a result per task,
+// even when some or all of them failed.
+func (h *Handler) Bulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
@@ -184,31 +454,709 @@ func (h *Handler) ListByProject(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page <= 0 {
-		page = 1
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	ch := BulkChange{Status: req.Status, Priority: req.Priority, Archive: req.Archive, Delete: req.Delete, OverrideBlockers: req.OverrideBlockers}
+	ids, err := parseIDList(req.TaskIDs)
+	if err == nil {
+		ch.AddAssignees, err = parseIDList(req.AddAssigneeIDs)
+	}
+	if err == nil {
+		ch.RemoveAssignees, err = parseIDList(req.RemoveAssigneeIDs)
 	}
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
-	if pageSize <= 0 {
-		pageSize = common.DefaultPageSize
+	if err == nil {
+		ch.AddLabels, err = parseIDList(req.AddLabelIDs)
 	}
-	if pageSize > common.MaxPageSize {
-		pageSize = common.MaxPageSize
+	if err == nil {
+		ch.RemoveLabels, err = parseIDList(req.RemoveLabelIDs)
 	}
-	skip := int64((page - 1) * pageSize)
-	limit := int64(pageSize)
-	list, total, err := h.svc.ListByProject(r.Context(), pid, skip, limit)
+	if err == nil && req.ProjectID != "" {
+		var target primitive.ObjectID
+		target, err = primitive.ObjectIDFromHex(req.ProjectID)
+		ch.MoveTo = &target
+	}
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	report, err := h.svc.Bulk(r.Context(), wsID, pid, Actor{UserID: userID, Role: u.Role}, ids, ch)
 	if err != nil {
 		common.Error(w, err)
 		return
 	}
-	if list == nil {
-		list = []*Task{}
+	common.OK(w, report)
+}
+
+func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+		common.Error(w, common.ErrBadRequest)
+		return
 	}
-	common.OK(w, map[string]any{
-		"items":        list,
-		"page":         page,
-		"page_size":    pageSize,
-		"total_count":  total,
-	})
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err := h.svc.GetByID(r.Context(), tid)
+	if err != nil {
+		common.Error(w, common.ErrNotFound)
+		return
+	}
+	if !CanUpdateTaskStatusOrPriority(u.Role, t.IsAssignee(userID)) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	version, err := common.IfMatch(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	var req struct {
+		Status           TaskStatus `json:"status"`
+		OverrideBlockers bool       `json:"override_blockers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Status == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err = h.svc.UpdateStatus(r.Context(), Actor{UserID: userID, Role: u.Role}, tid, version, req.Status, req.OverrideBlockers)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	common.SetETag(w, t.Version)
+	common.NoContent(w)
+}
+
+func (h *Handler) UpdatePriority(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err := h.svc.GetByID(r.Context(), tid)
+	if err != nil {
+		common.Error(w, common.ErrNotFound)
+		return
+	}
+	if !CanUpdateTaskStatusOrPriority(u.Role, t.IsAssignee(userID)) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	version, err := common.IfMatch(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	var req struct {
+		Priority TaskPriority `json:"priority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Priority == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err = h.svc.UpdatePriority(r.Context(), tid, version, req.Priority)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	common.SetETag(w, t.Version)
+	common.NoContent(w)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanUpdateTaskFull(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	version, err := common.IfMatch(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	decode := decodeReplace
+	if r.Method == http.MethodPatch {
+		decode = decodeMergePatch
+	}
+	patch, override, err := decode(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	t, err := h.svc.Update(r.Context(), Actor{UserID: userID, Role: u.Role}, tid, version, patch, override)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	common.SetETag(w, t.Version)
+	common.NoContent(w)
+}
+
+// decodeMergePatch reads a PatchRequest into a Patch.
+func decodeMergePatch(r *http.Request) (Patch, bool, error) {
+	if err := common.CheckMergePatch(r); err != nil {
+		return Patch{}, false, err
+	}
+	var req PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Patch{}, false, common.ErrBadRequest
+	}
+	if req.Title.Null || req.Status.Null || req.Priority.Null {
+		return Patch{}, false, fmt.Errorf("%w: title, status and priority cannot be null", common.ErrInvalidInput)
+	}
+	p := Patch{
+		Title:          req.Title.Ptr(),
+		Description:    req.Description.Ptr(),
+		Status:         req.Status.Ptr(),
+		Priority:       req.Priority.Ptr(),
+		StartDate:      req.StartDate.Ptr(),
+		ClearStartDate: req.StartDate.Null,
+		DueDate:        req.DueDate.Ptr(),
+		ClearDueDate:   req.DueDate.Null,
+		Estimate:       req.Estimate.Ptr(),
+		ClearEstimate:  req.Estimate.Null,
+	}
+	if req.Description.Null {
+		empty := ""
+		p.Description = &empty
+	}
+	return p, req.OverrideBlockers, nil
+}
+
+// decodeReplace reads an UpdateRequest into a Patch that sets every editable field.
+func decodeReplace(r *http.Request) (Patch, bool, error) {
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Patch{}, false, common.ErrBadRequest
+	}
+	if req.Title == "" || req.Status == "" || req.Priority == "" {
+		return Patch{}, false, fmt.Errorf("%w: PUT replaces the task; title, status and priority are required", common.ErrInvalidInput)
+	}
+	return Patch{
+		Title:          &req.Title,
+		Description:    &req.Description,
+		Status:         &req.Status,
+		Priority:       &req.Priority,
+		StartDate:      req.StartDate,
+		ClearStartDate: req.StartDate == nil,
+		DueDate:        req.DueDate,
+		ClearDueDate:   req.DueDate == nil,
+		Estimate:       req.Estimate,
+		ClearEstimate:  req.Estimate == nil,
+	}, req.OverrideBlockers, nil
+}
+
+// updateError replies to a failed conditional update. A version conflict gets 412 with the current
+// task and its ETag so the client can merge and retry; other errors map as usual.
+func updateError(w http.ResponseWriter, err error) {
+	var conflict *VersionConflictError
+	if errors.As(err, &conflict) {
+		common.SetETag(w, conflict.Current.Version)
+		common.JSON(w, http.StatusPreconditionFailed, common.ErrorResp{Error: conflict.Error(), Data: conflict.Current})
+		return
+	}
+	common.Error(w, err)
+}
+
+// ListByProject handles GET /workspaces/:id/projects/:pid/tasks. Pages by page/page_size, or by
+// cursor when the cursor parameter is present; count=false skips total_count.
+func (h *Handler) ListByProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	pid, err := primitive.ObjectIDFromHex(r.PathValue("pid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	f, err := parseListFilter(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.ListByProject(r.Context(), pid, f, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// AssignRequest is the JSON body for POST .../tasks/:tid/assignees.
+type AssignRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// Assign handles POST /workspaces/:id/projects/:pid/tasks/:tid/assignees.
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanAssignTask(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Assign(r.Context(), wsID, pid, tid, userID); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// Unassign handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/assignees/:uid.
+func (h *Handler) Unassign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanAssignTask(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(r.PathValue("uid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Unassign(r.Context(), wsID, pid, tid, userID); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// ListMine handles GET /workspaces/:id/tasks/mine (tasks assigned to me across the workspace).
+func (h *Handler) ListMine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	f, err := parseListFilter(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.ListAssignedInWorkspace(r.Context(), wsID, userID, f, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// ListOverdue handles GET /workspaces/:id/tasks/overdue (open tasks past their due date).
+func (h *Handler) ListOverdue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.ListOverdue(r.Context(), wsID, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// RelationRequest is the JSON body for POST .../tasks/:tid/relations.
+type RelationRequest struct {
+	RelatedID string       `json:"related_id"`
+	Type      RelationType `json:"type"`
+}
+
+// AddRelation handles POST /workspaces/:id/projects/:pid/tasks/:tid/relations.
+func (h *Handler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanUpdateTaskFull(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	tid, err := primitive.ObjectIDFromHex(r.PathValue("tid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req RelationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RelatedID == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	relatedID, err := primitive.ObjectIDFromHex(req.RelatedID)
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	rel, err := h.svc.AddRelation(r.Context(), wsID, Actor{UserID: userID, Role: u.Role}, tid, relatedID, req.Type)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, rel)
+}
+
+// ListRelations handles GET /workspaces/:id/projects/:pid/tasks/:tid/relations.
+func (h *Handler) ListRelations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	list, err := h.svc.ListRelations(r.Context(), wsID, pid, tid)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, list)
+}
+
+// RemoveRelation handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/relations/:rid.
+func (h *Handler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanUpdateTaskFull(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	rid, err := primitive.ObjectIDFromHex(r.PathValue("rid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.RemoveRelation(r.Context(), wsID, pid, tid, rid); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// LabelRequest is the JSON body for POST .../tasks/:tid/labels.
+type LabelRequest struct {
+	LabelID string `json:"label_id"`
+}
+
+// AddLabel handles POST /workspaces/:id/projects/:pid/tasks/:tid/labels.
+func (h *Handler) AddLabel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanUpdateTaskFull(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req LabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.LabelID == "" {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	labelID, err := primitive.ObjectIDFromHex(req.LabelID)
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.AddLabel(r.Context(), wsID, pid, tid, labelID); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// RemoveLabel handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/labels/:lid.
+func (h *Handler) RemoveLabel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	if u == nil || !CanUpdateTaskFull(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	labelID, err := primitive.ObjectIDFromHex(r.PathValue("lid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.RemoveLabel(r.Context(), wsID, pid, tid, labelID); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// parseListFilter reads task list filters and sort order from the query:
+//
+//	label, status, priority, assignee, created_by   any of the values (repeated or comma-separated)
+//	cycle                                           any of the cycles, or "none" for tasks in no cycle
+//	module                                          any of the modules
+//	due_within=N                                    due between now and N days from now
+//	due_after, due_before, created_after, ...       inclusive date bounds (RFC 3339 or YYYY-MM-DD)
+//	q                                               text in title, description or key
+//	sort                                            e.g. -priority,due_date (see ParseSort)
+//	include_archived, include_deleted               also list archived or trashed tasks
+func parseListFilter(r *http.Request) (ListFilter, error) {
+	var f ListFilter
+	q := r.URL.Query()
+	var err error
+	if f.LabelIDs, err = parseIDList(q["label"]); err != nil {
+		return f, fmt.Errorf("%w: invalid label id", common.ErrBadRequest)
+	}
+	if f.AssigneeIDs, err = parseIDList(q["assignee"]); err != nil {
+		return f, fmt.Errorf("%w: invalid assignee id", common.ErrBadRequest)
+	}
+	if f.CreatedBy, err = parseIDList(q["created_by"]); err != nil {
+		return f, fmt.Errorf("%w: invalid created_by id", common.ErrBadRequest)
+	}
+	if cycles := splitValues(q["cycle"]); len(cycles) == 1 && cycles[0] == "none" {
+		f.NoCycle = true
+	} else if f.CycleIDs, err = parseIDList(cycles); err != nil {
+		return f, fmt.Errorf("%w: invalid cycle id", common.ErrBadRequest)
+	}
+	if f.ModuleIDs, err = parseIDList(q["module"]); err != nil {
+		return f, fmt.Errorf("%w: invalid module id", common.ErrBadRequest)
+	}
+	for _, v := range splitValues(q["status"]) {
+		f.Statuses = append(f.Statuses, TaskStatus(strings.ToUpper(v)))
+	}
+	for _, v := range splitValues(q["priority"]) {
+		p := TaskPriority(strings.ToUpper(v))
+		if p != PriorityLow && p != PriorityMedium && p != PriorityHigh {
+			return f, fmt.Errorf("%w: unknown priority %q", common.ErrBadRequest, v)
+		}
+		f.Priorities = append(f.Priorities, p)
+	}
+	if v := q.Get("due_within"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			return f, common.ErrBadRequest
+		}
+		now := time.Now()
+		until := now.AddDate(0, 0, days)
+		f.DueFrom, f.DueTo = &now, &until
+	}
+	bounds := []struct {
+		param string
+		dst   **time.Time
+		end   bool
+	}{
+		{"due_after", &f.DueFrom, false},
+		{"due_before", &f.DueTo, true},
+		{"created_after", &f.CreatedFrom, false},
+		{"created_before", &f.CreatedTo, true},
+		{"updated_after", &f.UpdatedFrom, false},
+		{"updated_before", &f.UpdatedTo, true},
+	}
+	for _, b := range bounds {
+		v := q.Get(b.param)
+		if v == "" {
+			continue
+		}
+		t, err := parseBound(v, b.end)
+		if err != nil {
+			return f, fmt.Errorf("%w: %s must be RFC 3339 or YYYY-MM-DD", common.ErrBadRequest, b.param)
+		}
+		*b.dst = &t
+	}
+	f.Text = strings.TrimSpace(q.Get("q"))
+	if f.Sort, err = ParseSort(q.Get("sort")); err != nil {
+		return f, err
+	}
+	f.IncludeArchived = q.Get("include_archived") == "true"
+	f.IncludeDeleted = q.Get("include_deleted") == "true"
+	return f, nil
+}
+
+// parseBound parses a date bound. A bare date means the start of that day (UTC), or its last
+// instant when end is set, so that "before" bounds include the whole day.
+func parseBound(v string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return t, err
+	}
+	if end {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// splitValues flattens repeated and/or comma-separated query values, dropping blanks.
+func splitValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+// parseIDList parses hex IDs from repeated and/or comma-separated query values.
+func parseIDList(values []string) ([]primitive.ObjectID, error) {
+	var out []primitive.ObjectID
+	for _, hex := range splitValues(values) {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// taskPath parses the workspace, project and task IDs from the route.
+func taskPath(r *http.Request) (wsID, pid, tid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if tid, err = primitive.ObjectIDFromHex(r.PathValue("tid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	return wsID, pid, tid, true
 }
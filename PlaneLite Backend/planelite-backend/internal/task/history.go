@@ -0,0 +1,118 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"planelite-backend/internal/cycle"
+)
+
+// StatusChange records a task entering a status, so reports such as cycle burndowns can tell what
+// state a task was in on a past day. From is empty for a new task and for cascaded changes.
+type StatusChange struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	TaskID primitive.ObjectID `bson:"task_id" json:"task_id"`
+	From   TaskStatus         `bson:"from,omitempty" json:"from,omitempty"`
+	To     TaskStatus         `bson:"to" json:"to"`
+	At     time.Time          `bson:"at" json:"at"`
+}
+
+// HistoryRepository stores task status changes.
+type HistoryRepository struct {
+	col *mongo.Collection
+}
+
+func NewHistoryRepository(db *mongo.Database) *HistoryRepository {
+	return &HistoryRepository{col: db.Collection("task_status_changes")}
+}
+
+func (r *HistoryRepository) Add(ctx context.Context, changes []StatusChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	docs := make([]any, len(changes))
+	for i := range changes {
+		docs[i] = changes[i]
+	}
+	_, err := r.col.InsertMany(ctx, docs)
+	return err
+}
+
+// ListByTasks returns the status changes of the tasks, oldest first.
+func (r *HistoryRepository) ListByTasks(ctx context.Context, taskIDs []primitive.ObjectID) ([]*StatusChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*StatusChange
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// recordStatus stores that the tasks ids went from one status to another. Failures are not surfaced since the
+// change itself already succeeded; reports fall back to the current status.
+func (s *Service) recordStatus(ctx context.Context, from, to TaskStatus, ids ...primitive.ObjectID) {
+	now := time.Now()
+	changes := make([]StatusChange, len(ids))
+	for i, id := range ids {
+		changes[i] = StatusChange{TaskID: id, From: from, To: to, At: now}
+	}
+	_ = s.history.Add(ctx, changes)
+}
+
+// StatusTimelines returns when each task was closed and reopened, for cycle burndowns. Tasks that
+// changed before status history was kept count as in their first recorded (or current) state all along.
+// Purged tasks are rebuilt from their history alone, judged by the states of projectID; purged tasks
+// without history are left out.
+func (s *Service) StatusTimelines(ctx context.Context, projectID primitive.ObjectID, ids []primitive.ObjectID) ([]cycle.StatusTimeline, error) {
+	if len(ids) == 0 {
+		return []cycle.StatusTimeline{}, nil
+	}
+	changes, err := s.history.ListByTasks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byTask := map[primitive.ObjectID][]*StatusChange{}
+	for _, c := range changes {
+		byTask[c.TaskID] = append(byTask[c.TaskID], c)
+	}
+	closedSets := map[primitive.ObjectID]map[TaskStatus]bool{}
+	out := make([]cycle.StatusTimeline, 0, len(ids))
+	for _, id := range ids {
+		list := byTask[id]
+		t, err := s.repo.FindByID(ctx, id)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if len(list) == 0 {
+				continue
+			}
+			t = &Task{ID: id, ProjectID: projectID}
+		} else if err != nil {
+			return nil, err
+		}
+		closed, ok := closedSets[t.ProjectID]
+		if !ok {
+			if closed, err = s.closedSet(ctx, t.ProjectID); err != nil {
+				return nil, err
+			}
+			closedSets[t.ProjectID] = closed
+		}
+		tl := cycle.StatusTimeline{TaskID: id, Estimate: t.Estimate, Initially: closed[t.Status]}
+		if len(list) > 0 {
+			tl.Initially = list[0].From != "" && closed[list[0].From]
+			for _, c := range list {
+				tl.Changes = append(tl.Changes, cycle.ClosedChange{At: c.At, Closed: closed[c.To]})
+			}
+		}
+		out = append(out, tl)
+	}
+	return out, nil
+}
@@ -1,11 +1,17 @@
 package task
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
 )
 
+// TaskStatus holds the key of one of the project's workflow states (see package state).
+// The constants below are the keys of the states every project is seeded with.
 type TaskStatus string
 
 const (
@@ -27,9 +33,151 @@ type Task struct {
 	Title       string             `bson:"title"`
 	Description string             `bson:"description"`
 	ProjectID   primitive.ObjectID `bson:"project_id"`
+	Sequence    int64              `bson:"sequence"` // per-project number, from the counters collection
+	Key         string             `bson:"key"`      // project identifier and sequence, e.g. WEB-123
 	Status      TaskStatus         `bson:"status"`
 	Priority    TaskPriority       `bson:"priority"`
-	CreatedBy   primitive.ObjectID `bson:"created_by"`
-	CreatedAt   time.Time          `bson:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at"`
+	// Estimate is in the unit of the project's estimate scheme: points (T-shirt sizes map to points)
+	// or hours. Unset when the task is not estimated or the project has no scheme.
+	Estimate    *float64             `bson:"estimate,omitempty"`
+	AssigneeIDs []primitive.ObjectID `bson:"assignee_ids"`
+	LabelIDs    []primitive.ObjectID `bson:"label_ids"`
+	StartDate   *time.Time           `bson:"start_date,omitempty"`
+	DueDate     *time.Time           `bson:"due_date,omitempty"`
+	ParentID    *primitive.ObjectID  `bson:"parent_id,omitempty"`
+	CycleID     *primitive.ObjectID  `bson:"cycle_id,omitempty"`   // the cycle (sprint) the task is planned in
+	ModuleIDs   []primitive.ObjectID `bson:"module_ids,omitempty"` // the modules (epics) the task belongs to
+	ArchivedAt  *time.Time           `bson:"archived_at,omitempty"`
+	DeletedAt   *time.Time           `bson:"deleted_at,omitempty"` // set while the task is in the trash
+	DeletedBy   *primitive.ObjectID  `bson:"deleted_by,omitempty"`
+	CreatedBy   primitive.ObjectID   `bson:"created_by"`
+	CreatedAt   time.Time            `bson:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"`
+	Version     int64                `bson:"version"` // bumped by every write; served as the ETag
+}
+
+// EstimateInput is an estimate as clients send it: a label of the project's scale ("M", "8") or a
+// number of hours, as a JSON string or number. project.EstimateScheme.Parse turns it into the stored value.
+type EstimateInput string
+
+func (e *EstimateInput) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*e = EstimateInput(n.String())
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*e = EstimateInput(s)
+	return nil
+}
+
+// Progress summarizes how many of a set of tasks are closed (completed or cancelled).
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Detail is a task as returned by GetByID, with derived data about related tasks.
+type Detail struct {
+	*Task
+	SubTasks  Progress    `json:"sub_tasks"`
+	Relations []*Relation `json:"relations"`
+}
+
+// FormatKey returns the human-readable key of task number seq in the project with identifier ident.
+func FormatKey(ident string, seq int64) string {
+	return ident + "-" + strconv.FormatInt(seq, 10)
+}
+
+// ParseKey splits a key such as WEB-123 into identifier and sequence.
+func ParseKey(key string) (ident string, seq int64, ok bool) {
+	i := strings.LastIndexByte(key, '-')
+	if i <= 0 {
+		return "", 0, false
+	}
+	seq, err := strconv.ParseInt(key[i+1:], 10, 64)
+	if err != nil || seq <= 0 {
+		return "", 0, false
+	}
+	return strings.ToUpper(key[:i]), seq, true
+}
+
+// IsAssignee reports whether userID is one of the task's assignees.
+func (t *Task) IsAssignee(userID primitive.ObjectID) bool {
+	for _, id := range t.AssigneeIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ParentCompletion decides what happens when a task with open sub-tasks is moved to a completed state.
+type ParentCompletion string
+
+const (
+	// ParentCompletionBlock rejects the move until every sub-task is closed.
+	ParentCompletionBlock ParentCompletion = "block"
+	// ParentCompletionCascade moves open sub-tasks (recursively) to the parent's new state.
+	ParentCompletionCascade ParentCompletion = "cascade"
+)
+
+// Actor identifies the user performing a task change; workflow rules are checked against its role.
+type Actor struct {
+	UserID primitive.ObjectID
+	Role   common.Role
+}
+
+// VersionConflictError is returned when a conditional update finds the task at another version than
+// the client's If-Match. Current is the task as it is now.
+type VersionConflictError struct {
+	Current *Task
+}
+
+func (e *VersionConflictError) Error() string {
+	return "task was changed by someone else; reload it and retry"
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return common.ErrPreconditionFailed
+}
+
+// ListFilter narrows and orders task listings; zero values mean no constraint.
+type ListFilter struct {
+	// LabelIDs matches tasks carrying any of the labels.
+	LabelIDs []primitive.ObjectID
+	// Statuses, Priorities, AssigneeIDs and CreatedBy match tasks with any of the given values.
+	Statuses    []TaskStatus
+	Priorities  []TaskPriority
+	AssigneeIDs []primitive.ObjectID
+	CreatedBy   []primitive.ObjectID
+	// CycleIDs matches tasks in any of the cycles; NoCycle matches tasks in none (the backlog).
+	CycleIDs []primitive.ObjectID
+	NoCycle  bool
+	// ModuleIDs matches tasks in any of the modules.
+	ModuleIDs []primitive.ObjectID
+	// DueFrom and DueTo bound due_date (inclusive); tasks without a due date never match.
+	DueFrom *time.Time
+	DueTo   *time.Time
+	// CreatedFrom/CreatedTo and UpdatedFrom/UpdatedTo bound created_at and updated_at (inclusive).
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	// Text matches title, description or key, case-insensitively.
+	Text string
+	// Sort orders the results; empty keeps the listing's default order.
+	Sort []SortKey
+	// Archived and trashed tasks are left out unless asked for.
+	IncludeArchived bool
+	IncludeDeleted  bool
+}
+
+// SortKey orders a task listing by one field; see ParseSort for the accepted fields.
+type SortKey struct {
+	Field string
+	Desc  bool
 }
@@ -0,0 +1,318 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/activity"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+)
+
+// Copier duplicates data hanging off a task (comments, attachments) onto a copy of the task.
+type Copier interface {
+	CopyTask(ctx context.Context, fromTaskID, toTaskID, projectID primitive.ObjectID) error
+}
+
+// MoveOptions select what a move takes along. Comments, attachments and mentions always follow the task.
+type MoveOptions struct {
+	// Subtasks moves the whole sub-task tree; otherwise sub-tasks stay behind and move up to the task's parent.
+	Subtasks bool
+	// Labels keeps the labels that apply to the target project; otherwise all labels are dropped.
+	Labels bool
+}
+
+// CopyOptions select what a copy duplicates besides the task's own fields.
+type CopyOptions struct {
+	Subtasks    bool
+	Comments    bool
+	Attachments bool
+	Labels      bool
+}
+
+// Move re-homes a task into another project of the same workspace. Each moved task gets the next key of
+// the target project and its old key keeps resolving through GetByKey. Statuses the target project has
+// no state for are remapped (see remapStatus), estimates that do not fit its estimate scheme are dropped
+// and moved tasks leave their cycle and modules, since those belong to one project. A moved task also
+// leaves its parent, since parents and sub-tasks share a project.
+func (s *Service) Move(ctx context.Context, workspaceID primitive.ObjectID, actor Actor, id, targetID primitive.ObjectID, opts MoveOptions) (*Task, error) {
+	before, err := s.findInWorkspace(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.move(ctx, workspaceID, id, targetID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if t.ProjectID != before.ProjectID {
+		s.record(ctx, workspaceID, t.ProjectID, t.ID, actor.UserID, activity.KindTaskUpdated, map[string]any{
+			"moved_from": before.Key,
+			"key":        t.Key,
+		})
+	}
+	return t, nil
+}
+
+// move does the work of Move without recording activity; Bulk records its own entry.
+func (s *Service) move(ctx context.Context, workspaceID, id, targetID primitive.ObjectID, opts MoveOptions) (*Task, error) {
+	t, err := s.findInWorkspace(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.ProjectID == targetID {
+		return t, nil
+	}
+	target, err := s.targetProject(ctx, workspaceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := []*Task{t}
+	if opts.Subtasks {
+		if tree, err = s.subtree(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+	statuses := map[TaskStatus]TaskStatus{}
+	for _, m := range tree {
+		if err := s.rehome(ctx, workspaceID, m, target, opts.Labels, statuses); err != nil {
+			return nil, err
+		}
+	}
+	if t.ParentID != nil {
+		if err := s.repo.SetParent(ctx, id, nil, common.AnyVersion); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.Subtasks {
+		if err := s.repo.ReparentChildren(ctx, id, t.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// rehome moves one task document into target, leaving a redirect from its old key. statuses caches
+// remapped statuses across the tasks of one move.
+func (s *Service) rehome(ctx context.Context, workspaceID primitive.ObjectID, t *Task, target *project.Project, keepLabels bool, statuses map[TaskStatus]TaskStatus) error {
+	status, ok := statuses[t.Status]
+	if !ok {
+		var err error
+		if status, err = s.remapStatus(ctx, t.ProjectID, target.ID, t.Status); err != nil {
+			return err
+		}
+		statuses[t.Status] = status
+	}
+	labels := []primitive.ObjectID{}
+	if keepLabels {
+		labels = s.applicableLabels(ctx, workspaceID, target.ID, t.LabelIDs)
+	}
+	seq, err := s.repo.NextSequence(ctx, target.ID)
+	if err != nil {
+		return err
+	}
+	unset := []string{"cycle_id", "module_ids"}
+	if t.Estimate != nil && fitEstimate(target, t.Estimate) == nil {
+		unset = append(unset, "estimate")
+	}
+	if err := s.repo.PatchIfVersion(ctx, t.ID, common.AnyVersion, bson.M{
+		"project_id": target.ID,
+		"sequence":   seq,
+		"key":        FormatKey(target.Identifier, seq),
+		"status":     status,
+		"label_ids":  labels,
+	}, unset); err != nil {
+		return err
+	}
+	if status != t.Status {
+		s.recordStatus(ctx, t.Status, status, t.ID)
+	}
+	if t.Sequence > 0 {
+		if err := s.repo.AddRedirect(ctx, t.ProjectID, t.Sequence, t.ID); err != nil {
+			return err
+		}
+	}
+	for _, d := range s.Dependents {
+		if err := d.MoveTask(ctx, t.ID, target.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy duplicates a task into a project of the same workspace (its own project included) under a new
+// key. The copy starts unarchived, with the caller as creator; a copy within the project keeps the
+// parent, a copy elsewhere is top-level. Mentions in the description are not notified again.
+func (s *Service) Copy(ctx context.Context, workspaceID primitive.ObjectID, actor Actor, id, targetID primitive.ObjectID, opts CopyOptions) (*Task, error) {
+	t, err := s.findInWorkspace(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.targetProject(ctx, workspaceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var parentID *primitive.ObjectID
+	if t.ProjectID == targetID {
+		parentID = t.ParentID
+	}
+	statuses := map[TaskStatus]TaskStatus{}
+	dup, err := s.copyTree(ctx, workspaceID, actor, t, target, parentID, opts, statuses, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, workspaceID, dup.ProjectID, dup.ID, actor.UserID, activity.KindTaskCreated, map[string]any{
+		"copied_from": t.Key,
+	})
+	return dup, nil
+}
+
+// copyTree copies src under parentID and, when opts.Subtasks is set, its live sub-tasks below the copy.
+func (s *Service) copyTree(ctx context.Context, workspaceID primitive.ObjectID, actor Actor, src *Task, target *project.Project, parentID *primitive.ObjectID, opts CopyOptions, statuses map[TaskStatus]TaskStatus, depth int) (*Task, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: sub-tasks nest too deeply", common.ErrInvalidInput)
+	}
+	status, ok := statuses[src.Status]
+	if !ok {
+		var err error
+		if status, err = s.remapStatus(ctx, src.ProjectID, target.ID, src.Status); err != nil {
+			return nil, err
+		}
+		statuses[src.Status] = status
+	}
+	labels := []primitive.ObjectID{}
+	if opts.Labels {
+		labels = s.applicableLabels(ctx, workspaceID, target.ID, src.LabelIDs)
+	}
+	seq, err := s.repo.NextSequence(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	dup := &Task{
+		Title:       src.Title,
+		Description: src.Description,
+		ProjectID:   target.ID,
+		Sequence:    seq,
+		Key:         FormatKey(target.Identifier, seq),
+		Status:      status,
+		Priority:    src.Priority,
+		Estimate:    fitEstimate(target, src.Estimate),
+		AssigneeIDs: append([]primitive.ObjectID{}, src.AssigneeIDs...),
+		LabelIDs:    labels,
+		StartDate:   src.StartDate,
+		DueDate:     src.DueDate,
+		ParentID:    parentID,
+		CreatedBy:   actor.UserID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, dup); err != nil {
+		return nil, err
+	}
+	s.recordStatus(ctx, "", dup.Status, dup.ID)
+	if opts.Comments && s.Comments != nil {
+		if err := s.Comments.CopyTask(ctx, src.ID, dup.ID, target.ID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Attachments && s.Attachments != nil {
+		if err := s.Attachments.CopyTask(ctx, src.ID, dup.ID, target.ID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Subtasks {
+		children, err := s.repo.ListChildren(ctx, src.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range children {
+			if _, err := s.copyTree(ctx, workspaceID, actor, c, target, &dup.ID, opts, statuses, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return dup, nil
+}
+
+// targetProject returns the destination of a move or copy, which must be in the workspace.
+func (s *Service) targetProject(ctx context.Context, workspaceID, targetID primitive.ObjectID) (*project.Project, error) {
+	target, err := s.projects.GetByID(ctx, targetID)
+	if err != nil || target.WorkspaceID != workspaceID {
+		return nil, fmt.Errorf("%w: target project must be in the same workspace", common.ErrInvalidInput)
+	}
+	return target, nil
+}
+
+// remapStatus maps a status of project from onto project to: the same key if to has it, else the first
+// state of to in the same group (a custom "QA" state lands in the target's first started state), else
+// the target's default state.
+func (s *Service) remapStatus(ctx context.Context, from, to primitive.ObjectID, status TaskStatus) (TaskStatus, error) {
+	if _, err := s.states.Get(ctx, to, string(status)); err == nil {
+		return status, nil
+	} else if !errors.Is(err, common.ErrInvalidInput) {
+		return "", err
+	}
+	if src, err := s.states.Get(ctx, from, string(status)); err == nil {
+		list, err := s.states.List(ctx, to)
+		if err != nil {
+			return "", err
+		}
+		for _, st := range list {
+			if st.Group == src.Group {
+				return TaskStatus(st.Key), nil
+			}
+		}
+	}
+	def, err := s.states.Default(ctx, to)
+	if err != nil {
+		return "", err
+	}
+	return TaskStatus(def.Key), nil
+}
+
+// fitEstimate returns estimate if it is a value of target's estimate scheme, else nil.
+func fitEstimate(target *project.Project, estimate *float64) *float64 {
+	if estimate == nil || !target.EstimateScheme.Fits(*estimate) {
+		return nil
+	}
+	v := *estimate
+	return &v
+}
+
+// applicableLabels keeps the labels that may be attached to tasks of projectID.
+func (s *Service) applicableLabels(ctx context.Context, workspaceID, projectID primitive.ObjectID, ids []primitive.ObjectID) []primitive.ObjectID {
+	out := []primitive.ObjectID{}
+	for _, l := range ids {
+		if s.labels.CheckApplicable(ctx, workspaceID, projectID, l) == nil {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// subtree returns t followed by its live descendants, parents before children.
+func (s *Service) subtree(ctx context.Context, t *Task) ([]*Task, error) {
+	out := []*Task{t}
+	level := []*Task{t}
+	for depth := 0; len(level) > 0; depth++ {
+		if depth > maxDepth {
+			return nil, fmt.Errorf("%w: sub-tasks nest too deeply", common.ErrInvalidInput)
+		}
+		var next []*Task
+		for _, p := range level {
+			children, err := s.repo.ListChildren(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, children...)
+		}
+		out = append(out, next...)
+		level = next
+	}
+	return out, nil
+}
@@ -9,12 +9,20 @@ func CanCreateTask(role common.Role) bool {
 	return role == common.RoleAdmin || role == common.RoleProjectManager
 }
 
-// CanUpdateTaskStatusOrPriority: developers/users can update status & priority; admin full.
-func CanUpdateTaskStatusOrPriority(role common.Role) bool {
-	return role == common.RoleAdmin || role == common.RoleProjectManager || role == common.RoleUser
+// CanUpdateTaskStatusOrPriority: admin and PROJECT_MANAGER on any task; USER only on tasks assigned to them.
+func CanUpdateTaskStatusOrPriority(role common.Role, isAssignee bool) bool {
+	if role == common.RoleAdmin || role == common.RoleProjectManager {
+		return true
+	}
+	return role == common.RoleUser && isAssignee
 }
 
 // CanUpdateTaskFull: admin and PROJECT_MANAGER can update all fields.
 func CanUpdateTaskFull(role common.Role) bool {
 	return role == common.RoleAdmin || role == common.RoleProjectManager
 }
+
+// CanAssignTask: admin and PROJECT_MANAGER can assign and unassign members.
+func CanAssignTask(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
@@ -0,0 +1,164 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+// priorityRankField is computed during sorted queries so priorities order LOW < MEDIUM < HIGH
+// rather than alphabetically.
+const priorityRankField = "priority_rank"
+
+// sortFields maps the names accepted in sort= to task document fields. Only these can be sorted on.
+var sortFields = map[string]string{
+	"title":      "title",
+	"key":        "sequence",
+	"status":     "status",
+	"priority":   priorityRankField,
+	"created_by": "created_by",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"start_date": "start_date",
+	"due_date":   "due_date",
+	"estimate":   "estimate",
+}
+
+var priorityRank = bson.M{"$switch": bson.M{
+	"branches": bson.A{
+		bson.M{"case": bson.M{"$eq": bson.A{"$priority", PriorityHigh}}, "then": 3},
+		bson.M{"case": bson.M{"$eq": bson.A{"$priority", PriorityMedium}}, "then": 2},
+		bson.M{"case": bson.M{"$eq": bson.A{"$priority", PriorityLow}}, "then": 1},
+	},
+	"default": 0,
+}}
+
+// ParseSort parses a comma-separated list of field names, each optionally prefixed with "-" for
+// descending order, e.g. "-priority,due_date".
+func ParseSort(v string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortFields[k.Field]; !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", common.ErrInvalidInput, k.Field)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// sortDoc returns the Mongo sort for keys, or def when keys is empty, ending with _id so that
+// pages are stable.
+func sortDoc(keys []SortKey, def bson.D) bson.D {
+	out := bson.D{}
+	if len(keys) == 0 {
+		out = append(out, def...)
+	}
+	for _, k := range keys {
+		dir := 1
+		if k.Desc {
+			dir = -1
+		}
+		out = append(out, bson.E{Key: sortFields[k.Field], Value: dir})
+	}
+	for _, e := range out {
+		if e.Key == "_id" {
+			return out
+		}
+	}
+	return append(out, bson.E{Key: "_id", Value: 1})
+}
+
+// needsPriorityRank reports whether sort refers to the computed priority rank.
+func needsPriorityRank(sort bson.D) bool {
+	for _, e := range sort {
+		if e.Key == priorityRankField {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFilter adds the ListFilter constraints to filter and returns it. Values are always compared
+// as data, never interpreted as operators.
+func applyFilter(filter bson.M, f ListFilter) bson.M {
+	if len(f.LabelIDs) > 0 {
+		addCond(filter, "label_ids", bson.M{"$in": f.LabelIDs})
+	}
+	if len(f.Statuses) > 0 {
+		addCond(filter, "status", bson.M{"$in": f.Statuses})
+	}
+	if len(f.Priorities) > 0 {
+		addCond(filter, "priority", bson.M{"$in": f.Priorities})
+	}
+	if len(f.AssigneeIDs) > 0 {
+		addCond(filter, "assignee_ids", bson.M{"$in": f.AssigneeIDs})
+	}
+	if len(f.CreatedBy) > 0 {
+		addCond(filter, "created_by", bson.M{"$in": f.CreatedBy})
+	}
+	if len(f.CycleIDs) > 0 {
+		addCond(filter, "cycle_id", bson.M{"$in": f.CycleIDs})
+	}
+	if f.NoCycle {
+		addCond(filter, "cycle_id", nil)
+	}
+	if len(f.ModuleIDs) > 0 {
+		addCond(filter, "module_ids", bson.M{"$in": f.ModuleIDs})
+	}
+	if r := dateRange(f.DueFrom, f.DueTo); r != nil {
+		r["$ne"] = nil
+		addCond(filter, "due_date", r)
+	}
+	if r := dateRange(f.CreatedFrom, f.CreatedTo); r != nil {
+		addCond(filter, "created_at", r)
+	}
+	if r := dateRange(f.UpdatedFrom, f.UpdatedTo); r != nil {
+		addCond(filter, "updated_at", r)
+	}
+	if f.Text != "" {
+		re := primitive.Regex{Pattern: regexp.QuoteMeta(f.Text), Options: "i"}
+		addCond(filter, "$or", []bson.M{{"title": re}, {"description": re}, {"key": re}})
+	}
+	if !f.IncludeArchived {
+		filter["archived_at"] = nil
+	}
+	if !f.IncludeDeleted {
+		filter["deleted_at"] = nil
+	}
+	return filter
+}
+
+// addCond sets filter[key] = cond, moving it under $and when key is already constrained.
+func addCond(filter bson.M, key string, cond any) {
+	if _, taken := filter[key]; !taken {
+		filter[key] = cond
+		return
+	}
+	and, _ := filter["$and"].([]bson.M)
+	filter["$and"] = append(and, bson.M{key: cond})
+}
+
+// dateRange returns an inclusive range condition, or nil when both bounds are unset.
+func dateRange(from, to *time.Time) bson.M {
+	if from == nil && to == nil {
+		return nil
+	}
+	r := bson.M{}
+	if from != nil {
+		r["$gte"] = *from
+	}
+	if to != nil {
+		r["$lte"] = *to
+	}
+	return r
+}
@@ -0,0 +1,57 @@
+package task
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RelationType is the kind of link between two tasks. Every relation is stored on both tasks,
+// the second copy using the inverse type.
+type RelationType string
+
+const (
+	RelationBlocks       RelationType = "blocks"
+	RelationBlockedBy    RelationType = "blocked_by"
+	RelationRelatesTo    RelationType = "relates_to"
+	RelationDuplicateOf  RelationType = "duplicate_of"
+	RelationDuplicatedBy RelationType = "duplicated_by"
+)
+
+// Inverse returns the type stored on the related task.
+func (t RelationType) Inverse() RelationType {
+	switch t {
+	case RelationBlocks:
+		return RelationBlockedBy
+	case RelationBlockedBy:
+		return RelationBlocks
+	case RelationDuplicateOf:
+		return RelationDuplicatedBy
+	case RelationDuplicatedBy:
+		return RelationDuplicateOf
+	default:
+		return t
+	}
+}
+
+// Valid reports whether t is a known relation type.
+func (t RelationType) Valid() bool {
+	switch t {
+	case RelationBlocks, RelationBlockedBy, RelationRelatesTo, RelationDuplicateOf, RelationDuplicatedBy:
+		return true
+	}
+	return false
+}
+
+// Relation links TaskID to RelatedID, read as "TaskID <type> RelatedID".
+type Relation struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID `bson:"workspace_id" json:"workspace_id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+	RelatedID   primitive.ObjectID `bson:"related_id" json:"related_id"`
+	Type        RelationType       `bson:"type" json:"type"`
+
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
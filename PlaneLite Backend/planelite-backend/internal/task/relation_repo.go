@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RelationRepository handles the task_relations collection.
+type RelationRepository struct {
+	col *mongo.Collection
+}
+
+func NewRelationRepository(db *mongo.Database) *RelationRepository {
+	return &RelationRepository{col: db.Collection("task_relations")}
+}
+
+// CreatePair stores rel and its inverse on the related task in one transaction, so a relation is
+// never left one-sided. rel.ID is set to the first document's ID.
+func (r *RelationRepository) CreatePair(ctx context.Context, rel *Relation) error {
+	docs := []any{
+		bson.M{
+			"workspace_id": rel.WorkspaceID,
+			"task_id":      rel.TaskID,
+			"related_id":   rel.RelatedID,
+			"type":         rel.Type,
+			"created_by":   rel.CreatedBy,
+			"created_at":   rel.CreatedAt,
+		},
+		bson.M{
+			"workspace_id": rel.WorkspaceID,
+			"task_id":      rel.RelatedID,
+			"related_id":   rel.TaskID,
+			"type":         rel.Type.Inverse(),
+			"created_by":   rel.CreatedBy,
+			"created_at":   rel.CreatedAt,
+		},
+	}
+	res, err := r.withTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return r.col.InsertMany(sc, docs, options.InsertMany().SetOrdered(true))
+	})
+	if err != nil {
+		return err
+	}
+	if result, ok := res.(*mongo.InsertManyResult); ok && len(result.InsertedIDs) > 0 {
+		if oid, ok := result.InsertedIDs[0].(primitive.ObjectID); ok {
+			rel.ID = oid
+		}
+	}
+	return nil
+}
+
+func (r *RelationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*Relation, error) {
+	var rel Relation
+	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&rel)
+	if err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// ListByTask returns relations stored on taskID, optionally limited to one type.
+func (r *RelationRepository) ListByTask(ctx context.Context, taskID primitive.ObjectID, typ RelationType) ([]*Relation, error) {
+	filter := bson.M{"task_id": taskID}
+	if typ != "" {
+		filter["type"] = typ
+	}
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Relation
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = []*Relation{}
+	}
+	return out, nil
+}
+
+// DeletePair removes rel and its inverse in one transaction, like CreatePair.
+func (r *RelationRepository) DeletePair(ctx context.Context, rel *Relation) error {
+	_, err := r.withTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return r.col.DeleteMany(sc, bson.M{"$or": []bson.M{
+			{"task_id": rel.TaskID, "related_id": rel.RelatedID, "type": rel.Type},
+			{"task_id": rel.RelatedID, "related_id": rel.TaskID, "type": rel.Type.Inverse()},
+		}})
+	})
+	return err
+}
+
+// withTransaction runs fn in a multi-document transaction and returns its result.
+func (r *RelationRepository) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) (any, error)) (any, error) {
+	sess, err := r.col.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.EndSession(ctx)
+	return sess.WithTransaction(ctx, fn)
+}
+
+// DeleteByTask removes every relation to or from taskID.
+func (r *RelationRepository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.col.DeleteMany(ctx, bson.M{"$or": []bson.M{{"task_id": taskID}, {"related_id": taskID}}})
+	return err
+}
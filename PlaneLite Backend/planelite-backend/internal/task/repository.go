@@ -2,27 +2,125 @@ package task
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
 )
 
+// incVersion is part of every task update, so conditional writes (If-Match) notice concurrent changes.
+var incVersion = bson.M{"version": 1}
+
+// errVersionMismatch is returned by conditional updates when the task is no longer at the expected version.
+var errVersionMismatch = errors.New("task version mismatch")
+
 type Repository struct {
-	col *mongo.Collection
+	col       *mongo.Collection
+	counters  *mongo.Collection
+	redirects *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) *Repository {
-	return &Repository{col: db.Collection("tasks")}
+	return &Repository{col: db.Collection("tasks"), counters: db.Collection("counters"), redirects: db.Collection("task_redirects")}
 }
 
-func (r *Repository) Create(ctx context.Context, t *Task) error {
-	_, err := r.col.InsertOne(ctx, t)
+// NextSequence atomically increments and returns the project's task counter.
+func (r *Repository) NextSequence(ctx context.Context, projectID primitive.ObjectID) (int64, error) {
+	var doc struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": "tasks:" + projectID.Hex()},
+		bson.M{"$inc": bson.M{"seq": 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	return doc.Seq, err
+}
+
+// FindBySequence returns task number seq of the project.
+func (r *Repository) FindBySequence(ctx context.Context, projectID primitive.ObjectID, seq int64) (*Task, error) {
+	var t Task
+	err := r.col.FindOne(ctx, bson.M{"project_id": projectID, "sequence": seq}).Decode(&t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// AddRedirect records that task number seq of the project is now taskID, which moved elsewhere.
+func (r *Repository) AddRedirect(ctx context.Context, projectID primitive.ObjectID, seq int64, taskID primitive.ObjectID) error {
+	_, err := r.redirects.UpdateOne(ctx,
+		bson.M{"project_id": projectID, "sequence": seq},
+		bson.M{"$set": bson.M{"task_id": taskID, "created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// FindRedirect returns the task that used to be number seq of the project.
+func (r *Repository) FindRedirect(ctx context.Context, projectID primitive.ObjectID, seq int64) (primitive.ObjectID, error) {
+	var doc struct {
+		TaskID primitive.ObjectID `bson:"task_id"`
+	}
+	err := r.redirects.FindOne(ctx, bson.M{"project_id": projectID, "sequence": seq}).Decode(&doc)
+	return doc.TaskID, err
+}
+
+// DeleteRedirects removes the redirects to a task.
+func (r *Repository) DeleteRedirects(ctx context.Context, taskID primitive.ObjectID) error {
+	_, err := r.redirects.DeleteMany(ctx, bson.M{"task_id": taskID})
+	return err
+}
+
+// Rekey rewrites the keys of all tasks in the project to use identifier.
+func (r *Repository) Rekey(ctx context.Context, projectID primitive.ObjectID, identifier string) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"project_id": projectID, "sequence": bson.M{"$gt": 0}}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"key":     bson.M{"$concat": bson.A{identifier + "-", bson.M{"$toString": "$sequence"}}},
+			"version": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+		}}},
+	})
 	return err
 }
 
+// ListWithoutSequence returns tasks created before task keys existed, oldest first.
+func (r *Repository) ListWithoutSequence(ctx context.Context) ([]*Task, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{"$or": []bson.M{{"sequence": bson.M{"$exists": false}}, {"sequence": 0}}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Task
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetKey records the sequence and key of a task.
+func (r *Repository) SetKey(ctx context.Context, id primitive.ObjectID, seq int64, key string) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"sequence": seq, "key": key}, "$inc": incVersion})
+	return err
+}
+
+func (r *Repository) Create(ctx context.Context, t *Task) error {
+	t.Version = 1
+	result, err := r.col.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		t.ID = oid
+	}
+	return nil
+}
+
 func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Task, error) {
 	var t Task
 	err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&t)
@@ -33,31 +131,431 @@ func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Task
 }
 
 func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, update bson.M) error {
-	if update["updated_at"] == nil {
-		update["updated_at"] = time.Now()
+	return r.UpdateIfVersion(ctx, id, common.AnyVersion, update)
+}
+
+// UpdateIfVersion sets fields of the task if it is still at version, or regardless with
+// common.AnyVersion. It returns errVersionMismatch when the task has moved on.
+func (r *Repository) UpdateIfVersion(ctx context.Context, id primitive.ObjectID, version int64, update bson.M) error {
+	return r.PatchIfVersion(ctx, id, version, update, nil)
+}
+
+// PatchIfVersion is UpdateIfVersion that also removes the fields named in unset.
+func (r *Repository) PatchIfVersion(ctx context.Context, id primitive.ObjectID, version int64, set bson.M, unset []string) error {
+	if set["updated_at"] == nil {
+		set["updated_at"] = time.Now()
 	}
-	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-	return err
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		fields := bson.M{}
+		for _, f := range unset {
+			fields[f] = ""
+		}
+		update["$unset"] = fields
+	}
+	return r.updateOne(ctx, id, version, update)
 }
 
-func (r *Repository) ListByProject(ctx context.Context, projectID primitive.ObjectID, skip, limit int64) ([]*Task, int64, error) {
-	filter := bson.M{"project_id": projectID}
-	total, err := r.col.CountDocuments(ctx, filter)
+// updateOne applies update to the task, bumping its version, if the task is at version (any version
+// for common.AnyVersion). Tasks written before versions existed count as version 0.
+func (r *Repository) updateOne(ctx context.Context, id primitive.ObjectID, version int64, update bson.M) error {
+	filter := bson.M{"_id": id}
+	switch version {
+	case common.AnyVersion:
+	case 0:
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	default:
+		filter["version"] = version
+	}
+	update["$inc"] = incVersion
+	res, err := r.col.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return nil, 0, err
+		return err
+	}
+	if res.MatchedCount == 0 && version != common.AnyVersion {
+		return errVersionMismatch
+	}
+	return nil
+}
+
+func (r *Repository) CountByStatus(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error) {
+	return r.col.CountDocuments(ctx, bson.M{"project_id": projectID, "status": status})
+}
+
+func (r *Repository) ListByProject(ctx context.Context, projectID primitive.ObjectID, f ListFilter, p common.ListParams) (*common.ListPage[*Task], error) {
+	return r.findPage(ctx, listQuery(projectID, f), sortDoc(f.Sort, bson.D{{Key: "_id", Value: 1}}), p)
+}
+
+// listQuery translates a ListFilter into a Mongo filter for one project.
+func listQuery(projectID primitive.ObjectID, f ListFilter) bson.M {
+	return applyFilter(bson.M{"project_id": projectID}, f)
+}
+
+func (r *Repository) AddAssignee(ctx context.Context, id, userID primitive.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$addToSet": bson.M{"assignee_ids": userID},
+		"$set":      bson.M{"updated_at": time.Now()},
+		"$inc":      incVersion,
+	})
+	return err
+}
+
+func (r *Repository) RemoveAssignee(ctx context.Context, id, userID primitive.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$pull": bson.M{"assignee_ids": userID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// ListByAssignee returns tasks assigned to userID within the given projects, newest first.
+func (r *Repository) ListByAssignee(ctx context.Context, userID primitive.ObjectID, projectIDs []primitive.ObjectID, f ListFilter, p common.ListParams) (*common.ListPage[*Task], error) {
+	filter := applyFilter(bson.M{"assignee_ids": userID, "project_id": bson.M{"$in": projectIDs}}, f)
+	return r.findPage(ctx, filter, sortDoc(f.Sort, bson.D{{Key: "updated_at", Value: -1}}), p)
+}
+
+// ListOverdue returns tasks due before now that are not in one of their project's closed states,
+// earliest due date first. closedByProject maps each project to search to its closed state keys.
+func (r *Repository) ListOverdue(ctx context.Context, closedByProject map[primitive.ObjectID][]string, now time.Time, p common.ListParams) (*common.ListPage[*Task], error) {
+	if len(closedByProject) == 0 {
+		return &common.ListPage[*Task]{Items: []*Task{}, PageSize: p.PageSize}, nil
 	}
-	opts := options.Find().SetSkip(skip).SetLimit(limit)
-	cur, err := r.col.Find(ctx, filter, opts)
+	or := make([]bson.M, 0, len(closedByProject))
+	for projectID, closed := range closedByProject {
+		or = append(or, bson.M{"project_id": projectID, "status": bson.M{"$nin": closed}})
+	}
+	filter := bson.M{"due_date": bson.M{"$lt": now}, "$or": or, "archived_at": nil, "deleted_at": nil}
+	return r.findPage(ctx, filter, bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}, p)
+}
+
+// findPage returns the page of tasks matching filter selected by p. Sorting on priority ranks
+// priorities first.
+func (r *Repository) findPage(ctx context.Context, filter bson.M, sort bson.D, p common.ListParams) (*common.ListPage[*Task], error) {
+	var extra []bson.D
+	if needsPriorityRank(sort) {
+		extra = append(extra, bson.D{{Key: "$addFields", Value: bson.M{priorityRankField: priorityRank}}})
+	}
+	return common.FindPage[Task](ctx, r.col, filter, sort, p, extra...)
+}
+
+func (r *Repository) AddLabel(ctx context.Context, id, labelID primitive.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$addToSet": bson.M{"label_ids": labelID},
+		"$set":      bson.M{"updated_at": time.Now()},
+		"$inc":      incVersion,
+	})
+	return err
+}
+
+func (r *Repository) RemoveLabel(ctx context.Context, id, labelID primitive.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$pull": bson.M{"label_ids": labelID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// PullLabelFromAll removes labelID from every task that carries it.
+func (r *Repository) PullLabelFromAll(ctx context.Context, labelID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"label_ids": labelID}, bson.M{
+		"$pull": bson.M{"label_ids": labelID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// ListChildren returns the direct sub-tasks of parentID that are not in the trash, oldest first.
+func (r *Repository) ListChildren(ctx context.Context, parentID primitive.ObjectID) ([]*Task, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cur, err := r.col.Find(ctx, bson.M{"parent_id": parentID, "deleted_at": nil}, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer cur.Close(ctx)
 	var out []*Task
 	if err := cur.All(ctx, &out); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	if out == nil {
 		out = []*Task{}
 	}
-	return out, total, nil
+	return out, nil
+}
+
+// SetParent sets or, with a nil parentID, clears the task's parent if the task is at version
+// (see UpdateIfVersion).
+func (r *Repository) SetParent(ctx context.Context, id primitive.ObjectID, parentID *primitive.ObjectID, version int64) error {
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if parentID != nil {
+		update["$set"].(bson.M)["parent_id"] = *parentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+	return r.updateOne(ctx, id, version, update)
+}
+
+// ReparentChildren moves the direct sub-tasks of parentID under newParent, or makes them top-level when nil.
+func (r *Repository) ReparentChildren(ctx context.Context, parentID primitive.ObjectID, newParent *primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if newParent != nil {
+		update["$set"].(bson.M)["parent_id"] = *newParent
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+	update["$inc"] = incVersion
+	_, err := r.col.UpdateMany(ctx, bson.M{"parent_id": parentID}, update)
+	return err
+}
+
+// SetDeleted moves a task to the trash, or out of it when at is nil.
+func (r *Repository) SetDeleted(ctx context.Context, id primitive.ObjectID, at *time.Time, by *primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if at != nil {
+		update["$set"].(bson.M)["deleted_at"] = *at
+		update["$set"].(bson.M)["deleted_by"] = *by
+	} else {
+		update["$unset"] = bson.M{"deleted_at": "", "deleted_by": ""}
+	}
+	return r.updateOne(ctx, id, common.AnyVersion, update)
+}
+
+// SetArchived archives a task, or unarchives it when at is nil.
+func (r *Repository) SetArchived(ctx context.Context, id primitive.ObjectID, at *time.Time) error {
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if at != nil {
+		update["$set"].(bson.M)["archived_at"] = *at
+	} else {
+		update["$unset"] = bson.M{"archived_at": ""}
+	}
+	return r.updateOne(ctx, id, common.AnyVersion, update)
+}
+
+// ListTrash returns the project's trashed tasks, most recently deleted first.
+func (r *Repository) ListTrash(ctx context.Context, projectID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Task], error) {
+	filter := bson.M{"project_id": projectID, "deleted_at": bson.M{"$ne": nil}}
+	return r.findPage(ctx, filter, bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}}, p)
+}
+
+// ListTrashedBefore returns tasks that went to the trash before t.
+func (r *Repository) ListTrashedBefore(ctx context.Context, t time.Time) ([]*Task, error) {
+	cur, err := r.col.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": t}})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Task
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// UnsetEstimates removes the estimate of every task in the project.
+func (r *Repository) UnsetEstimates(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"project_id": projectID, "estimate": bson.M{"$exists": true}}, bson.M{
+		"$unset": bson.M{"estimate": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$inc":   incVersion,
+	})
+	return err
+}
+
+// EstimateTotals counts the project's live, unarchived tasks per status, with the number of estimated
+// tasks and the sum of their estimates.
+func (r *Repository) EstimateTotals(ctx context.Context, projectID primitive.ObjectID) ([]project.EstimateTotal, error) {
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": projectID, "deleted_at": nil, "archived_at": nil}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$status",
+			"tasks":     bson.M{"$sum": 1},
+			"estimated": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$ne": bson.A{bson.M{"$type": "$estimate"}, "missing"}}, 1, 0}}},
+			"total":     bson.M{"$sum": "$estimate"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var rows []struct {
+		Status    string  `bson:"_id"`
+		Tasks     int     `bson:"tasks"`
+		Estimated int     `bson:"estimated"`
+		Total     float64 `bson:"total"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]project.EstimateTotal, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, project.EstimateTotal{Status: row.Status, Tasks: row.Tasks, Estimated: row.Estimated, Total: row.Total})
+	}
+	return out, nil
+}
+
+// SetCycle puts the tasks into cycleID, or takes them out of any cycle when it is nil.
+func (r *Repository) SetCycle(ctx context.Context, ids []primitive.ObjectID, cycleID *primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}, "$inc": incVersion}
+	if cycleID != nil {
+		update["$set"].(bson.M)["cycle_id"] = *cycleID
+	} else {
+		update["$unset"] = bson.M{"cycle_id": ""}
+	}
+	_, err := r.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	return err
+}
+
+// UnsetCycle takes every task out of the cycle.
+func (r *Repository) UnsetCycle(ctx context.Context, cycleID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"cycle_id": cycleID}, bson.M{
+		"$unset": bson.M{"cycle_id": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$inc":   incVersion,
+	})
+	return err
+}
+
+// ListByCycle returns the live, unarchived tasks of the cycle.
+func (r *Repository) ListByCycle(ctx context.Context, cycleID primitive.ObjectID) ([]*Task, error) {
+	cur, err := r.col.Find(ctx, bson.M{"cycle_id": cycleID, "deleted_at": nil, "archived_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var out []*Task
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddModule adds moduleID to the modules of the tasks.
+func (r *Repository) AddModule(ctx context.Context, ids []primitive.ObjectID, moduleID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "module_ids": bson.M{"$ne": moduleID}}, bson.M{
+		"$push": bson.M{"module_ids": moduleID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// RemoveModule takes the task out of moduleID.
+func (r *Repository) RemoveModule(ctx context.Context, id, moduleID primitive.ObjectID) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id, "module_ids": moduleID}, bson.M{
+		"$pull": bson.M{"module_ids": moduleID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// PullModuleFromAll takes every task out of a deleted module.
+func (r *Repository) PullModuleFromAll(ctx context.Context, moduleID primitive.ObjectID) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"module_ids": moduleID}, bson.M{
+		"$pull": bson.M{"module_ids": moduleID},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
+	})
+	return err
+}
+
+// ModuleStatusCounts counts the live, unarchived tasks of each module per status, with the sum of
+// their estimates.
+func (r *Repository) ModuleStatusCounts(ctx context.Context, moduleIDs []primitive.ObjectID) (map[primitive.ObjectID][]project.EstimateTotal, error) {
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"module_ids": bson.M{"$in": moduleIDs}, "deleted_at": nil, "archived_at": nil}}},
+		{{Key: "$unwind", Value: "$module_ids"}},
+		{{Key: "$match", Value: bson.M{"module_ids": bson.M{"$in": moduleIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"module": "$module_ids", "status": "$status"},
+			"tasks": bson.M{"$sum": 1},
+			"total": bson.M{"$sum": "$estimate"},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var rows []struct {
+		ID struct {
+			Module primitive.ObjectID `bson:"module"`
+			Status string             `bson:"status"`
+		} `bson:"_id"`
+		Tasks int     `bson:"tasks"`
+		Total float64 `bson:"total"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := map[primitive.ObjectID][]project.EstimateTotal{}
+	for _, row := range rows {
+		out[row.ID.Module] = append(out[row.ID.Module], project.EstimateTotal{Status: row.ID.Status, Tasks: row.Tasks, Total: row.Total})
+	}
+	return out, nil
+}
+
+// MilestoneCount is the number of a milestone's tasks in one project and status, and of those due
+// after the milestone's target date.
+type MilestoneCount struct {
+	ProjectID primitive.ObjectID
+	Status    TaskStatus
+	Tasks     int
+	Late      int
+}
+
+// MilestoneCounts counts the live, unarchived tasks in any of the projects or modules or among
+// taskIDs, per project and status. Each task counts once.
+func (r *Repository) MilestoneCounts(ctx context.Context, projectIDs, moduleIDs, taskIDs []primitive.ObjectID, target time.Time) ([]MilestoneCount, error) {
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"$or": bson.A{
+				bson.M{"project_id": bson.M{"$in": projectIDs}},
+				bson.M{"module_ids": bson.M{"$in": moduleIDs}},
+				bson.M{"_id": bson.M{"$in": taskIDs}},
+			},
+			"deleted_at":  nil,
+			"archived_at": nil,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"project": "$project_id", "status": "$status"},
+			"tasks": bson.M{"$sum": 1},
+			"late":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$due_date", target}}, 1, 0}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var rows []struct {
+		ID struct {
+			Project primitive.ObjectID `bson:"project"`
+			Status  TaskStatus         `bson:"status"`
+		} `bson:"_id"`
+		Tasks int `bson:"tasks"`
+		Late  int `bson:"late"`
+	}
+	if err := cur.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]MilestoneCount, len(rows))
+	for i, row := range rows {
+		out[i] = MilestoneCount{ProjectID: row.ID.Project, Status: row.ID.Status, Tasks: row.Tasks, Late: row.Late}
+	}
+	return out, nil
+}
+
+// SetStatusMany moves every task in ids to status.
+func (r *Repository) SetStatusMany(ctx context.Context, ids []primitive.ObjectID, status TaskStatus) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{
+		"$set": bson.M{"status": status, "updated_at": time.Now()},
+		"$inc": incVersion,
+	})
+	return err
 }
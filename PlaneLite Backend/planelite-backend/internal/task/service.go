@@ -2,31 +2,127 @@ package task
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/activity"
 	"planelite-backend/internal/common"
+	"planelite-backend/internal/cycle"
+	"planelite-backend/internal/label"
+	"planelite-backend/internal/mention"
+	"planelite-backend/internal/milestone"
+	"planelite-backend/internal/module"
+	"planelite-backend/internal/project"
+	"planelite-backend/internal/state"
+	"planelite-backend/internal/workspace"
 )
 
+// maxDepth bounds parent-chain walks so corrupted data cannot loop forever.
+const maxDepth = 64
+
+// Dependent owns data hanging off tasks (attachments, comments, ...). It removes the data when a task is
+// purged from the trash and re-homes it when a task moves to another project.
+type Dependent interface {
+	DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error
+	MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error
+}
+
+// ScopeTracker is told when a task of a cycle is trashed or archived and when it is restored or
+// unarchived, so the cycle's scope history follows what its listings show. Implemented by cycle.Service.
+type ScopeTracker interface {
+	TaskHidden(ctx context.Context, t cycle.TaskInfo, userID primitive.ObjectID) error
+	TaskShown(ctx context.Context, t cycle.TaskInfo, userID primitive.ObjectID) error
+}
+
 type Service struct {
-	repo *Repository
+	repo       *Repository
+	history    *HistoryRepository
+	relRepo    *RelationRepository
+	projects   *project.Service
+	workspaces *workspace.Service
+	states     *state.Service
+	labels     *label.Service
+	mentions   *mention.Service
+	activity   *activity.Service
+	// ParentCompletion is the policy for completing tasks with open sub-tasks; defaults to block.
+	ParentCompletion ParentCompletion
+	// Dependents are cleaned up when a task is purged and follow it when it moves.
+	Dependents []Dependent
+	// Comments and Attachments duplicate a task's comments and files when it is copied; nil skips them.
+	Comments    Copier
+	Attachments Copier
+	// Cycles records trashed, archived and restored tasks in their cycle's scope; nil skips it.
+	Cycles ScopeTracker
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// CreateInput holds the fields of a new task; optional fields may be left zero.
+type CreateInput struct {
+	Title       string
+	Description string
+	StartDate   *time.Time
+	DueDate     *time.Time
+	ParentID    *primitive.ObjectID
+	// Estimate is checked against the project's estimate scheme; see project.EstimateScheme.Parse.
+	Estimate *EstimateInput
 }
 
-func (s *Service) Create(ctx context.Context, projectID, createdBy primitive.ObjectID, title, description string) (*Task, error) {
-	if title == "" {
+func NewService(repo *Repository, history *HistoryRepository, relRepo *RelationRepository, projects *project.Service, workspaces *workspace.Service, states *state.Service, labels *label.Service, mentions *mention.Service, activitySvc *activity.Service) *Service {
+	return &Service{repo: repo, history: history, relRepo: relRepo, projects: projects, workspaces: workspaces, states: states, labels: labels, mentions: mentions, activity: activitySvc}
+}
+
+func (s *Service) Create(ctx context.Context, projectID, createdBy primitive.ObjectID, in CreateInput) (*Task, error) {
+	if in.Title == "" {
 		return nil, common.ErrInvalidInput
 	}
+	if err := validateDates(in.StartDate, in.DueDate); err != nil {
+		return nil, err
+	}
+	if in.ParentID != nil {
+		parent, err := s.findLive(ctx, *in.ParentID)
+		if err != nil || parent.ProjectID != projectID {
+			return nil, fmt.Errorf("%w: parent task must exist in the same project", common.ErrInvalidInput)
+		}
+	}
+	def, err := s.states.Default(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.projects.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	var estimate *float64
+	if in.Estimate != nil {
+		v, err := p.EstimateScheme.Parse(string(*in.Estimate))
+		if err != nil {
+			return nil, err
+		}
+		estimate = &v
+	}
+	description, mentioned := s.mentions.Resolve(ctx, p.WorkspaceID, in.Description)
+	seq, err := s.repo.NextSequence(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
 	t := &Task{
-		Title:       title,
+		Title:       in.Title,
 		Description: description,
 		ProjectID:   projectID,
-		Status:      StatusTodo,
+		Sequence:    seq,
+		Key:         FormatKey(p.Identifier, seq),
+		Status:      TaskStatus(def.Key),
 		Priority:    PriorityMedium,
+		Estimate:    estimate,
+		AssigneeIDs: []primitive.ObjectID{},
+		LabelIDs:    []primitive.ObjectID{},
+		StartDate:   in.StartDate,
+		DueDate:     in.DueDate,
+		ParentID:    in.ParentID,
 		CreatedBy:   createdBy,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -34,6 +130,11 @@ func (s *Service) Create(ctx context.Context, projectID, createdBy primitive.Obj
 	if err := s.repo.Create(ctx, t); err != nil {
 		return nil, err
 	}
+	s.recordStatus(ctx, "", t.Status, t.ID)
+	ref := mention.Ref{WorkspaceID: p.WorkspaceID, ProjectID: projectID, TaskID: t.ID, AuthorID: createdBy}
+	if err := s.mentions.Sync(ctx, ref, description, mentioned); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -41,37 +142,1008 @@ func (s *Service) GetByID(ctx context.Context, id primitive.ObjectID) (*Task, er
 	return s.repo.FindByID(ctx, id)
 }
 
-func (s *Service) UpdateStatus(ctx context.Context, id primitive.ObjectID, status TaskStatus) error {
-	if status != StatusTodo && status != StatusInProgress && status != StatusDone {
-		return common.ErrInvalidInput
+// GetByKey resolves a key such as WEB-123 within the workspace. Keys using an identifier the project
+// had before a rename still resolve, and so do keys a task had before it moved to another project.
+func (s *Service) GetByKey(ctx context.Context, workspaceID primitive.ObjectID, key string) (*Detail, error) {
+	ident, seq, ok := ParseKey(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: task key must look like WEB-123", common.ErrInvalidInput)
+	}
+	p, err := s.projects.GetByIdentifier(ctx, workspaceID, ident)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if t, err := s.repo.FindBySequence(ctx, p.ID, seq); err == nil {
+		return s.GetDetail(ctx, t.ID)
+	}
+	id, err := s.repo.FindRedirect(ctx, p.ID, seq)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	return s.GetDetail(ctx, id)
+}
+
+// RekeyProject rewrites the project's task keys after its identifier changed; used by project.Service.
+func (s *Service) RekeyProject(ctx context.Context, projectID primitive.ObjectID, identifier string) error {
+	return s.repo.Rekey(ctx, projectID, identifier)
+}
+
+// ClearEstimates drops the estimates of the project's tasks after its estimate scheme changed; used by
+// project.Service.
+func (s *Service) ClearEstimates(ctx context.Context, projectID primitive.ObjectID) error {
+	return s.repo.UnsetEstimates(ctx, projectID)
+}
+
+// CycleTask returns what cycle.Service needs to know about a live task of the project.
+func (s *Service) CycleTask(ctx context.Context, projectID, id primitive.ObjectID) (*cycle.TaskInfo, error) {
+	t, err := s.findLive(ctx, id)
+	if err != nil || t.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	closed, err := s.closedSet(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &cycle.TaskInfo{ID: t.ID, CycleID: t.CycleID, Estimate: t.Estimate, Closed: closed[t.Status]}, nil
+}
+
+// CycleTasks returns the live, unarchived tasks of a cycle; used by cycle.Service.
+func (s *Service) CycleTasks(ctx context.Context, cycleID primitive.ObjectID) ([]cycle.TaskInfo, error) {
+	list, err := s.repo.ListByCycle(ctx, cycleID)
+	if err != nil {
+		return nil, err
+	}
+	closed := map[primitive.ObjectID]map[TaskStatus]bool{}
+	out := make([]cycle.TaskInfo, 0, len(list))
+	for _, t := range list {
+		if closed[t.ProjectID] == nil {
+			if closed[t.ProjectID], err = s.closedSet(ctx, t.ProjectID); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, cycle.TaskInfo{ID: t.ID, CycleID: t.CycleID, Estimate: t.Estimate, Closed: closed[t.ProjectID][t.Status]})
+	}
+	return out, nil
+}
+
+// SetCycle puts tasks into a cycle, or out of any with a nil cycleID; used by cycle.Service.
+func (s *Service) SetCycle(ctx context.Context, ids []primitive.ObjectID, cycleID *primitive.ObjectID) error {
+	return s.repo.SetCycle(ctx, ids, cycleID)
+}
+
+// ClearCycle takes every task out of a deleted cycle; used by cycle.Service.
+func (s *Service) ClearCycle(ctx context.Context, cycleID primitive.ObjectID) error {
+	return s.repo.UnsetCycle(ctx, cycleID)
+}
+
+// AddToModule adds tasks to a module; used by module.Service.
+func (s *Service) AddToModule(ctx context.Context, ids []primitive.ObjectID, moduleID primitive.ObjectID) error {
+	return s.repo.AddModule(ctx, ids, moduleID)
+}
+
+// RemoveFromModule takes a task out of a module; used by module.Service.
+func (s *Service) RemoveFromModule(ctx context.Context, id, moduleID primitive.ObjectID) error {
+	return s.repo.RemoveModule(ctx, id, moduleID)
+}
+
+// ClearModule takes every task out of a deleted module; used by module.Service.
+func (s *Service) ClearModule(ctx context.Context, moduleID primitive.ObjectID) error {
+	return s.repo.PullModuleFromAll(ctx, moduleID)
+}
+
+// ModuleProgress rolls up the tasks of the project's modules: closed (completed or cancelled) against
+// all tasks, and the estimates of each. Modules without tasks get a zero Progress.
+func (s *Service) ModuleProgress(ctx context.Context, projectID primitive.ObjectID, moduleIDs []primitive.ObjectID) (map[primitive.ObjectID]module.Progress, error) {
+	out := map[primitive.ObjectID]module.Progress{}
+	if len(moduleIDs) == 0 {
+		return out, nil
+	}
+	counts, err := s.repo.ModuleStatusCounts(ctx, moduleIDs)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.closedSet(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for id, rows := range counts {
+		var p module.Progress
+		for _, row := range rows {
+			p.TotalTasks += row.Tasks
+			p.TotalPoints += row.Total
+			if closed[TaskStatus(row.Status)] {
+				p.DoneTasks += row.Tasks
+				p.DonePoints += row.Total
+			}
+		}
+		out[id] = p
+	}
+	return out, nil
+}
+
+// MilestoneProgress counts the tasks a milestone covers: all, closed (completed or cancelled), and
+// open ones due after its target date. Used by milestone.Service.
+func (s *Service) MilestoneProgress(ctx context.Context, m *milestone.Milestone) (milestone.Progress, error) {
+	var p milestone.Progress
+	if len(m.ProjectIDs) == 0 && len(m.ModuleIDs) == 0 && len(m.TaskIDs) == 0 {
+		return p, nil
+	}
+	counts, err := s.repo.MilestoneCounts(ctx, m.ProjectIDs, m.ModuleIDs, m.TaskIDs, m.TargetDate)
+	if err != nil {
+		return p, err
+	}
+	closedSets := map[primitive.ObjectID]map[TaskStatus]bool{}
+	for _, c := range counts {
+		closed, ok := closedSets[c.ProjectID]
+		if !ok {
+			if closed, err = s.closedSet(ctx, c.ProjectID); err != nil {
+				return p, err
+			}
+			closedSets[c.ProjectID] = closed
+		}
+		p.TotalTasks += c.Tasks
+		if closed[c.Status] {
+			p.DoneTasks += c.Tasks
+		} else {
+			p.LateTasks += c.Late
+		}
+	}
+	return p, nil
+}
+
+// EstimateTotals sums the project's estimates per status, one row per workflow state in state order
+// (states without tasks included), followed by any statuses no longer backed by a state.
+func (s *Service) EstimateTotals(ctx context.Context, projectID primitive.ObjectID) ([]project.EstimateTotal, error) {
+	rows, err := s.repo.EstimateTotals(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.states.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	byStatus := map[string]project.EstimateTotal{}
+	for _, row := range rows {
+		byStatus[row.Status] = row
+	}
+	out := make([]project.EstimateTotal, 0, len(states)+len(rows))
+	for _, st := range states {
+		row, ok := byStatus[st.Key]
+		if !ok {
+			row = project.EstimateTotal{Status: st.Key}
+		}
+		delete(byStatus, st.Key)
+		out = append(out, row)
+	}
+	for _, row := range rows {
+		if _, ok := byStatus[row.Status]; ok {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+// BackfillKeys numbers tasks created before task keys existed, in creation order per project.
+// Run after project.Service.BackfillIdentifiers.
+func (s *Service) BackfillKeys(ctx context.Context) error {
+	list, err := s.repo.ListWithoutSequence(ctx)
+	if err != nil {
+		return err
+	}
+	idents := map[primitive.ObjectID]string{}
+	for _, t := range list {
+		ident, ok := idents[t.ProjectID]
+		if !ok {
+			p, err := s.projects.GetByID(ctx, t.ProjectID)
+			if err != nil {
+				continue // task of a deleted project
+			}
+			ident, idents[t.ProjectID] = p.Identifier, p.Identifier
+		}
+		seq, err := s.repo.NextSequence(ctx, t.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SetKey(ctx, t.ID, seq, FormatKey(ident, seq)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckInProject returns ErrNotFound unless the task exists in the project.
+func (s *Service) CheckInProject(ctx context.Context, projectID, id primitive.ObjectID) error {
+	t, err := s.findLive(ctx, id)
+	if err != nil || t.ProjectID != projectID {
+		return common.ErrNotFound
+	}
+	return nil
+}
+
+// GetDetail returns the task with its sub-task completion roll-up and relations.
+func (s *Service) GetDetail(ctx context.Context, id primitive.ObjectID) (*Detail, error) {
+	t, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, common.ErrNotFound
 	}
-	return s.repo.Update(ctx, id, bson.M{"status": status, "updated_at": time.Now()})
+	children, err := s.repo.ListChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.closedSet(ctx, t.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.relRepo.ListByTask(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	d := &Detail{Task: t, SubTasks: Progress{Total: len(children)}, Relations: relations}
+	for _, c := range children {
+		if closed[c.Status] {
+			d.SubTasks.Done++
+		}
+	}
+	return d, nil
 }
 
-func (s *Service) UpdatePriority(ctx context.Context, id primitive.ObjectID, priority TaskPriority) error {
-	if priority != PriorityLow && priority != PriorityMedium && priority != PriorityHigh {
-		return common.ErrInvalidInput
+// UpdateStatus moves the task to status, which must be a state key of the task's project
+// reachable from the current status under the project's transition rules. Starting or completing
+// a task with open blockers fails unless overrideBlockers is set. Like the other updates it only
+// applies if the task is still at version (see checkVersion) and returns the updated task.
+func (s *Service) UpdateStatus(ctx context.Context, actor Actor, id primitive.ObjectID, version int64, status TaskStatus, overrideBlockers bool) (*Task, error) {
+	t, err := s.findLive(ctx, id)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if err := checkVersion(t, version); err != nil {
+		return nil, err
+	}
+	if err := s.checkStatusChange(ctx, actor, t, status, overrideBlockers); err != nil {
+		return nil, err
+	}
+	cascade, err := s.openDescendantsOnCompletion(ctx, actor, t, status, overrideBlockers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateIfVersion(ctx, id, version, bson.M{"status": status, "updated_at": time.Now()}); err != nil {
+		return nil, s.versionConflict(ctx, id, err)
+	}
+	if status != t.Status {
+		s.recordStatus(ctx, t.Status, status, id)
 	}
-	return s.repo.Update(ctx, id, bson.M{"priority": priority, "updated_at": time.Now()})
+	if err := s.cascadeStatus(ctx, cascade, status); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *Service) Update(ctx context.Context, id primitive.ObjectID, title, description string, status TaskStatus, priority TaskPriority) error {
+func (s *Service) UpdatePriority(ctx context.Context, id primitive.ObjectID, version int64, priority TaskPriority) (*Task, error) {
+	if !validPriority(priority) {
+		return nil, fmt.Errorf("%w: unknown priority %q", common.ErrInvalidInput, priority)
+	}
+	t, err := s.findLive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(t, version); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateIfVersion(ctx, id, version, bson.M{"priority": priority, "updated_at": time.Now()}); err != nil {
+		return nil, s.versionConflict(ctx, id, err)
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Patch lists the changes to a task; nil fields are left alone. Title, status and priority cannot be
+// emptied, an empty Description clears the description and ClearStartDate/ClearDueDate/ClearEstimate
+// remove a date or the estimate.
+type Patch struct {
+	Title          *string
+	Description    *string
+	Status         *TaskStatus
+	Priority       *TaskPriority
+	StartDate      *time.Time
+	ClearStartDate bool
+	DueDate        *time.Time
+	ClearDueDate   bool
+	Estimate       *EstimateInput
+	ClearEstimate  bool
+}
+
+// Update applies p to the task. Status changes follow the same rules as UpdateStatus; setting the
+// current status again is a no-op.
+func (s *Service) Update(ctx context.Context, actor Actor, id primitive.ObjectID, version int64, p Patch, overrideBlockers bool) (*Task, error) {
+	t, err := s.findLive(ctx, id)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if err := checkVersion(t, version); err != nil {
+		return nil, err
+	}
 	up := bson.M{"updated_at": time.Now()}
-	if title != "" {
-		up["title"] = title
+	var unset []string
+	if p.Title != nil {
+		if strings.TrimSpace(*p.Title) == "" {
+			return nil, fmt.Errorf("%w: title cannot be empty", common.ErrInvalidInput)
+		}
+		up["title"] = *p.Title
 	}
-	if description != "" {
+	var ref *mention.Ref
+	var description string
+	var mentioned []primitive.ObjectID
+	if p.Description != nil {
+		proj, err := s.projects.GetByID(ctx, t.ProjectID)
+		if err != nil {
+			return nil, common.ErrNotFound
+		}
+		description, mentioned = s.mentions.Resolve(ctx, proj.WorkspaceID, *p.Description)
+		ref = &mention.Ref{WorkspaceID: proj.WorkspaceID, ProjectID: t.ProjectID, TaskID: id, AuthorID: actor.UserID}
 		up["description"] = description
 	}
-	if status != "" {
+	var status TaskStatus
+	var cascade []primitive.ObjectID
+	if p.Status != nil && *p.Status != t.Status {
+		status = *p.Status
+		if err := s.checkStatusChange(ctx, actor, t, status, overrideBlockers); err != nil {
+			return nil, err
+		}
+		if cascade, err = s.openDescendantsOnCompletion(ctx, actor, t, status, overrideBlockers); err != nil {
+			return nil, err
+		}
 		up["status"] = status
 	}
-	if priority != "" {
-		up["priority"] = priority
+	if p.Priority != nil {
+		if !validPriority(*p.Priority) {
+			return nil, fmt.Errorf("%w: unknown priority %q", common.ErrInvalidInput, *p.Priority)
+		}
+		up["priority"] = *p.Priority
+	}
+	start, due := t.StartDate, t.DueDate
+	switch {
+	case p.ClearStartDate:
+		start = nil
+		unset = append(unset, "start_date")
+	case p.StartDate != nil:
+		start = p.StartDate
+		up["start_date"] = *p.StartDate
+	}
+	switch {
+	case p.ClearDueDate:
+		due = nil
+		unset = append(unset, "due_date")
+	case p.DueDate != nil:
+		due = p.DueDate
+		up["due_date"] = *p.DueDate
+	}
+	if err := validateDates(start, due); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.ClearEstimate:
+		unset = append(unset, "estimate")
+	case p.Estimate != nil:
+		proj, err := s.projects.GetByID(ctx, t.ProjectID)
+		if err != nil {
+			return nil, common.ErrNotFound
+		}
+		v, err := proj.EstimateScheme.Parse(string(*p.Estimate))
+		if err != nil {
+			return nil, err
+		}
+		up["estimate"] = v
+	}
+	if err := s.repo.PatchIfVersion(ctx, id, version, up, unset); err != nil {
+		return nil, s.versionConflict(ctx, id, err)
+	}
+	if status != "" {
+		s.recordStatus(ctx, t.Status, status, id)
+	}
+	if ref != nil {
+		if err := s.mentions.Sync(ctx, *ref, description, mentioned); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.cascadeStatus(ctx, cascade, status); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// checkStatusChange validates that status exists in the task's project, that actor may move t there,
+// and, unless overrideBlockers is set, that a task being started or completed has no open blockers.
+func (s *Service) checkStatusChange(ctx context.Context, actor Actor, t *Task, status TaskStatus, overrideBlockers bool) error {
+	st, err := s.states.Get(ctx, t.ProjectID, string(status))
+	if err != nil {
+		return err
+	}
+	if err := s.states.CheckTransition(ctx, t.ProjectID, string(t.Status), string(status), actor.Role); err != nil {
+		return err
+	}
+	if overrideBlockers || (st.Group != state.GroupStarted && st.Group != state.GroupCompleted) {
+		return nil
+	}
+	open, err := s.openBlockers(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: task is blocked by %d open task(s); set override_blockers to proceed", common.ErrConflict, len(open))
+	}
+	return nil
+}
+
+// openBlockers returns the IDs of tasks blocking id that are not in a closed state.
+func (s *Service) openBlockers(ctx context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	rels, err := s.relRepo.ListByTask(ctx, id, RelationBlockedBy)
+	if err != nil {
+		return nil, err
+	}
+	var open []primitive.ObjectID
+	for _, rel := range rels {
+		b, err := s.findLive(ctx, rel.RelatedID)
+		if err != nil {
+			continue // blocker was deleted
+		}
+		st, err := s.states.Get(ctx, b.ProjectID, string(b.Status))
+		if err != nil || !st.Group.Closed() {
+			open = append(open, b.ID)
+		}
+	}
+	return open, nil
+}
+
+// openDescendantsOnCompletion applies the parent-completion policy when t moves to status.
+// Under "block" it fails if t has open sub-tasks; under "cascade" it returns the open descendants
+// to move along with t, after checking that actor could move each of them directly (see
+// checkCascade). Moves to non-completed states return nil.
+func (s *Service) openDescendantsOnCompletion(ctx context.Context, actor Actor, t *Task, status TaskStatus, overrideBlockers bool) ([]primitive.ObjectID, error) {
+	st, err := s.states.Get(ctx, t.ProjectID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	if st.Group != state.GroupCompleted {
+		return nil, nil
+	}
+	closed, err := s.closedSet(ctx, t.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	var open []*Task
+	queue := []primitive.ObjectID{t.ID}
+	for depth := 0; len(queue) > 0 && depth < maxDepth; depth++ {
+		var next []primitive.ObjectID
+		for _, id := range queue {
+			children, err := s.repo.ListChildren(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range children {
+				if !closed[c.Status] {
+					open = append(open, c)
+				}
+				next = append(next, c.ID)
+			}
+		}
+		queue = next
+	}
+	if len(open) > 0 && s.ParentCompletion != ParentCompletionCascade {
+		return nil, fmt.Errorf("%w: task has %d open sub-task(s)", common.ErrConflict, len(open))
+	}
+	if err := s.checkCascade(ctx, actor, t, open, status, overrideBlockers); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(open))
+	for i, c := range open {
+		ids[i] = c.ID
+	}
+	return ids, nil
+}
+
+// checkCascade fails the parent's move unless actor could move each sub-task to status directly: the
+// workflow allows the transition and, unless overrideBlockers is set, the sub-task has no open
+// blocker other than tasks completed in the same move.
+func (s *Service) checkCascade(ctx context.Context, actor Actor, parent *Task, subtasks []*Task, status TaskStatus, overrideBlockers bool) error {
+	moving := make(map[primitive.ObjectID]bool, len(subtasks)+1)
+	moving[parent.ID] = true
+	for _, c := range subtasks {
+		moving[c.ID] = true
+	}
+	for _, c := range subtasks {
+		if err := s.states.CheckTransition(ctx, c.ProjectID, string(c.Status), string(status), actor.Role); err != nil {
+			return fmt.Errorf("sub-task %s: %w", c.Key, err)
+		}
+		if overrideBlockers {
+			continue
+		}
+		open, err := s.openBlockers(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		for _, b := range open {
+			if !moving[b] {
+				return fmt.Errorf("%w: sub-task %s is blocked by an open task; set override_blockers to proceed", common.ErrConflict, c.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// cascadeStatus moves sub-tasks collected by openDescendantsOnCompletion to status.
+func (s *Service) cascadeStatus(ctx context.Context, ids []primitive.ObjectID, status TaskStatus) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.repo.SetStatusMany(ctx, ids, status); err != nil {
+		return err
 	}
-	return s.repo.Update(ctx, id, up)
+	s.recordStatus(ctx, "", status, ids...)
+	return nil
 }
 
-func (s *Service) ListByProject(ctx context.Context, projectID primitive.ObjectID, skip, limit int64) ([]*Task, int64, error) {
-	return s.repo.ListByProject(ctx, projectID, skip, limit)
+// closedSet returns the project's completed and cancelled state keys as a set.
+func (s *Service) closedSet(ctx context.Context, projectID primitive.ObjectID) (map[TaskStatus]bool, error) {
+	keys, err := s.states.ClosedKeys(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[TaskStatus]bool, len(keys))
+	for _, k := range keys {
+		set[TaskStatus(k)] = true
+	}
+	return set, nil
+}
+
+// ListChildren returns the direct sub-tasks of a task of the project.
+func (s *Service) ListChildren(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) ([]*Task, error) {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListChildren(ctx, id)
+}
+
+// SetParent re-parents a task within its project; a nil parentID makes it top-level.
+// Parents that would create a cycle are rejected.
+func (s *Service) SetParent(ctx context.Context, workspaceID, projectID, id primitive.ObjectID, version int64, parentID *primitive.ObjectID) (*Task, error) {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	t, err := s.findLive(ctx, id)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if err := checkVersion(t, version); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, *parentID); err != nil {
+			return nil, fmt.Errorf("%w: parent task must exist in the same project", common.ErrInvalidInput)
+		}
+		parent, err := s.findLive(ctx, *parentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: parent task must exist in the same project", common.ErrInvalidInput)
+		}
+		for cur, depth := parent, 0; ; depth++ {
+			if cur.ID == id {
+				return nil, fmt.Errorf("%w: a task cannot be nested under its own sub-task", common.ErrInvalidInput)
+			}
+			if cur.ParentID == nil {
+				break
+			}
+			if depth >= maxDepth {
+				return nil, fmt.Errorf("%w: task hierarchy is too deep", common.ErrInvalidInput)
+			}
+			if cur, err = s.repo.FindByID(ctx, *cur.ParentID); err != nil {
+				break
+			}
+		}
+	}
+	if err := s.repo.SetParent(ctx, id, parentID, version); err != nil {
+		return nil, s.versionConflict(ctx, id, err)
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete moves a task to the trash. Trashed tasks are hidden from listings and read-only until
+// restored, and are purged for good after the retention period.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	t, err := s.findInWorkspaceProject(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.repo.SetDeleted(ctx, id, &now, &userID); err != nil {
+		return err
+	}
+	if t.ArchivedAt != nil {
+		return nil
+	}
+	return s.trackScope(ctx, t, false, userID)
+}
+
+// Restore takes a task out of the trash.
+func (s *Service) Restore(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	if err := s.checkProject(ctx, workspaceID, projectID); err != nil {
+		return err
+	}
+	t, err := s.repo.FindByID(ctx, id)
+	if err != nil || t.ProjectID != projectID {
+		return common.ErrNotFound
+	}
+	if t.DeletedAt == nil {
+		return fmt.Errorf("%w: task is not in the trash", common.ErrInvalidInput)
+	}
+	if err := s.repo.SetDeleted(ctx, id, nil, nil); err != nil {
+		return err
+	}
+	if t.ArchivedAt != nil {
+		return nil
+	}
+	return s.trackScope(ctx, t, true, userID)
+}
+
+// Archive hides a task from default listings without deleting it; Unarchive brings it back.
+func (s *Service) Archive(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	t, err := s.findInWorkspaceProject(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return err
+	}
+	if t.ArchivedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	if err := s.repo.SetArchived(ctx, id, &now); err != nil {
+		return err
+	}
+	return s.trackScope(ctx, t, false, userID)
+}
+
+func (s *Service) Unarchive(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	t, err := s.findInWorkspaceProject(ctx, workspaceID, projectID, id)
+	if err != nil {
+		return err
+	}
+	if t.ArchivedAt == nil {
+		return nil
+	}
+	if err := s.repo.SetArchived(ctx, id, nil); err != nil {
+		return err
+	}
+	return s.trackScope(ctx, t, true, userID)
+}
+
+// trackScope tells s.Cycles that t, if it is in a cycle, was hidden from listings or shown again.
+func (s *Service) trackScope(ctx context.Context, t *Task, shown bool, userID primitive.ObjectID) error {
+	if s.Cycles == nil || t.CycleID == nil {
+		return nil
+	}
+	info := cycle.TaskInfo{ID: t.ID, CycleID: t.CycleID, Estimate: t.Estimate}
+	if shown {
+		return s.Cycles.TaskShown(ctx, info, userID)
+	}
+	return s.Cycles.TaskHidden(ctx, info, userID)
+}
+
+// ListTrash returns the project's trashed tasks.
+func (s *Service) ListTrash(ctx context.Context, workspaceID, projectID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Task], error) {
+	if err := s.checkProject(ctx, workspaceID, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTrash(ctx, projectID, p)
+}
+
+// PurgeTrash permanently deletes tasks trashed before the cutoff and returns how many were purged.
+// A task that fails to purge is skipped, so it cannot hold up the others; the failures are returned
+// joined and the task is tried again on the next run.
+func (s *Service) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	list, err := s.repo.ListTrashedBefore(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	var errs []error
+	for _, t := range list {
+		if err := s.purge(ctx, t); err != nil {
+			errs = append(errs, fmt.Errorf("purge %s: %w", t.Key, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purge removes a task together with its dependents and relations. Its sub-tasks move up to the
+// task's own parent. The status history stays, like cycle scope events, so that past burndowns still
+// add up.
+func (s *Service) purge(ctx context.Context, t *Task) error {
+	for _, d := range s.Dependents {
+		if err := d.DeleteByTask(ctx, t.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.relRepo.DeleteByTask(ctx, t.ID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteRedirects(ctx, t.ID); err != nil {
+		return err
+	}
+	if err := s.repo.ReparentChildren(ctx, t.ID, t.ParentID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, t.ID)
+}
+
+// checkVersion fails with a VersionConflictError unless t is at version; common.AnyVersion matches any.
+func checkVersion(t *Task, version int64) error {
+	if version != common.AnyVersion && t.Version != version {
+		return &VersionConflictError{Current: t}
+	}
+	return nil
+}
+
+// versionConflict turns errVersionMismatch from a conditional write into a VersionConflictError
+// carrying the task as it is now; other errors pass through.
+func (s *Service) versionConflict(ctx context.Context, id primitive.ObjectID, err error) error {
+	if !errors.Is(err, errVersionMismatch) {
+		return err
+	}
+	t, ferr := s.repo.FindByID(ctx, id)
+	if ferr != nil {
+		return common.ErrPreconditionFailed
+	}
+	return &VersionConflictError{Current: t}
+}
+
+// findLive returns the task unless it is missing or in the trash.
+func (s *Service) findLive(ctx context.Context, id primitive.ObjectID) (*Task, error) {
+	t, err := s.repo.FindByID(ctx, id)
+	if err != nil || t.DeletedAt != nil {
+		return nil, common.ErrNotFound
+	}
+	return t, nil
+}
+
+// CountByStatus returns how many tasks of the project are in status; used by state.Service before deleting a state.
+func (s *Service) CountByStatus(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error) {
+	return s.repo.CountByStatus(ctx, projectID, status)
+}
+
+func (s *Service) ListByProject(ctx context.Context, projectID primitive.ObjectID, f ListFilter, p common.ListParams) (*common.ListPage[*Task], error) {
+	return s.repo.ListByProject(ctx, projectID, f, p)
+}
+
+// Assign adds userID to the task's assignees. The task must be in the project and workspace, and the
+// user an approved member of the workspace.
+func (s *Service) Assign(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	ok, err := s.workspaces.HasApprovedAccess(ctx, userID, workspaceID)
+	if err != nil {
+		return common.ErrNotFound
+	}
+	if !ok {
+		return fmt.Errorf("%w: user is not an approved workspace member", common.ErrInvalidInput)
+	}
+	return s.repo.AddAssignee(ctx, id, userID)
+}
+
+// Unassign removes userID from the task's assignees. The task must be in the project and workspace.
+func (s *Service) Unassign(ctx context.Context, workspaceID, projectID, id, userID primitive.ObjectID) error {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	return s.repo.RemoveAssignee(ctx, id, userID)
+}
+
+// ListAssignedInWorkspace returns tasks assigned to userID across all projects of the workspace.
+func (s *Service) ListAssignedInWorkspace(ctx context.Context, workspaceID, userID primitive.ObjectID, f ListFilter, p common.ListParams) (*common.ListPage[*Task], error) {
+	ids, err := s.workspaceProjectIDs(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByAssignee(ctx, userID, ids, f, p)
+}
+
+// ListOverdue returns tasks across the workspace whose due date has passed and whose state is
+// neither completed nor cancelled.
+func (s *Service) ListOverdue(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Task], error) {
+	ids, err := s.workspaceProjectIDs(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	closed := make(map[primitive.ObjectID][]string, len(ids))
+	for _, id := range ids {
+		keys, err := s.states.ClosedKeys(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		closed[id] = keys
+	}
+	return s.repo.ListOverdue(ctx, closed, time.Now(), p)
+}
+
+func (s *Service) workspaceProjectIDs(ctx context.Context, workspaceID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	projects, err := s.projects.ListByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// AddLabel attaches a label of the workspace to the task. Project labels only fit tasks of that project.
+func (s *Service) AddLabel(ctx context.Context, workspaceID, projectID, id, labelID primitive.ObjectID) error {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	if err := s.labels.CheckApplicable(ctx, workspaceID, projectID, labelID); err != nil {
+		return err
+	}
+	return s.repo.AddLabel(ctx, id, labelID)
+}
+
+// RemoveLabel detaches a label from the task.
+func (s *Service) RemoveLabel(ctx context.Context, workspaceID, projectID, id, labelID primitive.ObjectID) error {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	return s.repo.RemoveLabel(ctx, id, labelID)
+}
+
+// DetachLabel removes a label from every task; called by label.Service when a label is deleted.
+func (s *Service) DetachLabel(ctx context.Context, labelID primitive.ObjectID) error {
+	return s.repo.PullLabelFromAll(ctx, labelID)
+}
+
+func validPriority(p TaskPriority) bool {
+	return p == PriorityLow || p == PriorityMedium || p == PriorityHigh
+}
+
+// validateDates requires start <= due when both are set.
+func validateDates(start, due *time.Time) error {
+	if start != nil && due != nil && start.After(*due) {
+		return fmt.Errorf("%w: start_date must not be after due_date", common.ErrInvalidInput)
+	}
+	return nil
+}
+
+// AddRelation links two tasks of the workspace and stores the inverse link on the related task.
+// Blocking links that would form a cycle are rejected.
+func (s *Service) AddRelation(ctx context.Context, workspaceID primitive.ObjectID, actor Actor, id, relatedID primitive.ObjectID, typ RelationType) (*Relation, error) {
+	if !typ.Valid() || id == relatedID {
+		return nil, common.ErrInvalidInput
+	}
+	for _, tid := range []primitive.ObjectID{id, relatedID} {
+		if err := s.CheckInWorkspace(ctx, workspaceID, tid); err != nil {
+			return nil, err
+		}
+	}
+	existing, err := s.relRepo.ListByTask(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range existing {
+		if rel.RelatedID == relatedID {
+			return nil, fmt.Errorf("%w: tasks are already related (%s)", common.ErrConflict, rel.Type)
+		}
+	}
+	switch typ {
+	case RelationBlocks:
+		err = s.checkNoBlockingPath(ctx, relatedID, id)
+	case RelationBlockedBy:
+		err = s.checkNoBlockingPath(ctx, id, relatedID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rel := &Relation{
+		WorkspaceID: workspaceID,
+		TaskID:      id,
+		RelatedID:   relatedID,
+		Type:        typ,
+		CreatedBy:   actor.UserID,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.relRepo.CreatePair(ctx, rel); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, common.ErrConflict
+		}
+		return nil, err
+	}
+	return rel, nil
+}
+
+// ListRelations returns the relations stored on a task of the project.
+func (s *Service) ListRelations(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) ([]*Relation, error) {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return nil, err
+	}
+	return s.relRepo.ListByTask(ctx, id, "")
+}
+
+// RemoveRelation deletes a relation of the task together with its inverse.
+func (s *Service) RemoveRelation(ctx context.Context, workspaceID, projectID, id, relationID primitive.ObjectID) error {
+	if err := s.checkInWorkspaceProject(ctx, workspaceID, projectID, id); err != nil {
+		return err
+	}
+	rel, err := s.relRepo.FindByID(ctx, relationID)
+	if err != nil || rel.TaskID != id {
+		return common.ErrNotFound
+	}
+	return s.relRepo.DeletePair(ctx, rel)
+}
+
+// checkNoBlockingPath fails if from already blocks to, directly or through a chain,
+// which would make a new "to blocks from" link circular.
+func (s *Service) checkNoBlockingPath(ctx context.Context, from, to primitive.ObjectID) error {
+	seen := map[primitive.ObjectID]bool{from: true}
+	queue := []primitive.ObjectID{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == to {
+			return fmt.Errorf("%w: blocking relation would create a cycle", common.ErrInvalidInput)
+		}
+		rels, err := s.relRepo.ListByTask(ctx, cur, RelationBlocks)
+		if err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			if !seen[rel.RelatedID] {
+				seen[rel.RelatedID] = true
+				queue = append(queue, rel.RelatedID)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckInWorkspace verifies the task exists and its project belongs to the workspace.
+func (s *Service) CheckInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) error {
+	_, err := s.findInWorkspace(ctx, workspaceID, id)
+	return err
+}
+
+// findInWorkspace returns a live task whose project belongs to the workspace, ErrNotFound otherwise.
+func (s *Service) findInWorkspace(ctx context.Context, workspaceID, id primitive.ObjectID) (*Task, error) {
+	t, err := s.findLive(ctx, id)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if err := s.checkProject(ctx, workspaceID, t.ProjectID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// checkInWorkspaceProject returns ErrNotFound unless the task is live, in the project, and the project
+// in the workspace.
+func (s *Service) checkInWorkspaceProject(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) error {
+	_, err := s.findInWorkspaceProject(ctx, workspaceID, projectID, id)
+	return err
+}
+
+// findInWorkspaceProject is checkInWorkspaceProject returning the task.
+func (s *Service) findInWorkspaceProject(ctx context.Context, workspaceID, projectID, id primitive.ObjectID) (*Task, error) {
+	t, err := s.findInWorkspace(ctx, workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.ProjectID != projectID {
+		return nil, common.ErrNotFound
+	}
+	return t, nil
+}
+
+// checkProject returns ErrNotFound unless the project belongs to the workspace.
+func (s *Service) checkProject(ctx context.Context, workspaceID, projectID primitive.ObjectID) error {
+	p, err := s.projects.GetByID(ctx, projectID)
+	if err != nil || p.WorkspaceID != workspaceID {
+		return common.ErrNotFound
+	}
+	return nil
 }
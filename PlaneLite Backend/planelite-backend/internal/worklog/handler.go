@@ -0,0 +1,437 @@
+package worklog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"planelite-backend/internal/common"
+)
+
+// dayLayout is the format of worklog dates and timesheet bounds in requests.
+const dayLayout = "2006-01-02"
+
+type Handler struct {
+	svc *Service
+}
+
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// CreateRequest is the JSON body for POST .../tasks/:tid/worklogs. Give either minutes or duration.
+type CreateRequest struct {
+	Minutes  int    `json:"minutes"`
+	Duration string `json:"duration"` // e.g. "1h30m"; rounded up to whole minutes
+	Date     string `json:"date"`     // YYYY-MM-DD; optional, defaults to today (UTC)
+	Note     string `json:"note"`
+}
+
+// UpdateRequest is the JSON body for PATCH .../tasks/:tid/worklogs/:wid. Absent fields are left unchanged.
+type UpdateRequest struct {
+	Minutes  *int    `json:"minutes"`
+	Duration string  `json:"duration"`
+	Date     string  `json:"date"`
+	Note     *string `json:"note"`
+}
+
+// StartTimerRequest is the optional JSON body for POST .../tasks/:tid/timer.
+type StartTimerRequest struct {
+	Note string `json:"note"`
+}
+
+// StopTimerRequest is the optional JSON body for POST /workspaces/:id/timer/stop.
+type StopTimerRequest struct {
+	Note    string `json:"note"`    // replaces the note given at start
+	Discard bool   `json:"discard"` // stop without logging the time
+}
+
+// Create handles POST /workspaces/:id/projects/:pid/tasks/:tid/worklogs.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	minutes, err := parseMinutes(req.Minutes, req.Duration)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	date, err := parseDay(req.Date)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	wl, err := h.svc.Log(r.Context(), wsID, pid, tid, userID, Input{Minutes: minutes, Date: date, Note: req.Note})
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, wl)
+}
+
+// List handles GET /workspaces/:id/projects/:pid/tasks/:tid/worklogs (paged; see common.ParseListParams).
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p, err := common.ParseListParams(r)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	page, err := h.svc.ListByTask(r.Context(), wsID, pid, tid, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, page)
+}
+
+// Update handles PATCH /workspaces/:id/projects/:pid/tasks/:tid/worklogs/:wid. Author or ADMIN.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wid, err := primitive.ObjectIDFromHex(r.PathValue("wid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	p := Patch{Minutes: req.Minutes, Note: req.Note}
+	if req.Duration != "" {
+		minutes, err := parseMinutes(0, req.Duration)
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		p.Minutes = &minutes
+	}
+	if p.Date, err = parseDay(req.Date); err != nil {
+		common.Error(w, err)
+		return
+	}
+	wl, err := h.svc.Update(r.Context(), wsID, pid, tid, wid, userID, u.Role, p)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, wl)
+}
+
+// Delete handles DELETE /workspaces/:id/projects/:pid/tasks/:tid/worklogs/:wid. Author or ADMIN.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	wid, err := primitive.ObjectIDFromHex(r.PathValue("wid"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	if err := h.svc.Delete(r.Context(), wsID, pid, tid, wid, userID, u.Role); err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.NoContent(w)
+}
+
+// StartTimer handles POST /workspaces/:id/projects/:pid/tasks/:tid/timer.
+func (h *Handler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, pid, tid, ok := taskPath(r)
+	if !ok {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req StartTimerRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+	}
+	t, err := h.svc.StartTimer(r.Context(), wsID, pid, tid, userID, req.Note)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.Created(w, t)
+}
+
+// CurrentTimer handles GET /workspaces/:id/timer: the caller's running timer, or 404.
+func (h *Handler) CurrentTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	t, err := h.svc.CurrentTimer(r.Context(), wsID, userID)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, t)
+}
+
+// StopTimer handles POST /workspaces/:id/timer/stop. Replies 201 with the new worklog, or 204 when
+// the time is discarded.
+func (h *Handler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	var req StopTimerRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+	}
+	wl, err := h.svc.StopTimer(r.Context(), wsID, userID, req.Note, req.Discard)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	if wl == nil {
+		common.NoContent(w)
+		return
+	}
+	common.Created(w, wl)
+}
+
+// Summary handles GET /workspaces/:id/time?group=task|project|user&project=&task=&user=&from=&to=.
+// Users who may not see others' time get their own totals only.
+func (h *Handler) Summary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	var f Filter
+	for param, dst := range map[string]**primitive.ObjectID{"project": &f.ProjectID, "task": &f.TaskID, "user": &f.UserID} {
+		v := q.Get(param)
+		if v == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+		*dst = &id
+	}
+	if !CanViewOthersTime(u.Role) {
+		if f.UserID != nil && *f.UserID != userID {
+			common.Error(w, common.ErrForbidden)
+			return
+		}
+		f.UserID = &userID
+	}
+	if f.From, err = parseDay(q.Get("from")); err != nil {
+		common.Error(w, err)
+		return
+	}
+	if f.To, err = parseDay(q.Get("to")); err != nil {
+		common.Error(w, err)
+		return
+	}
+	group := Group(q.Get("group"))
+	if group == "" {
+		group = GroupTask
+	}
+	totals, err := h.svc.Summary(r.Context(), wsID, group, f)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, totals)
+}
+
+// Timesheet handles GET /workspaces/:id/timesheets?user=&from=&to=. user defaults to the caller and
+// the range to the current week (Monday to Sunday, UTC); other users' timesheets need
+// CanViewOthersTime.
+func (h *Handler) Timesheet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	u := common.GetContextUser(r.Context())
+	userID, ok := contextUserID(w, r)
+	if !ok {
+		return
+	}
+	wsID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		common.Error(w, common.ErrBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	target := userID
+	if v := q.Get("user"); v != "" {
+		if target, err = primitive.ObjectIDFromHex(v); err != nil {
+			common.Error(w, common.ErrBadRequest)
+			return
+		}
+	}
+	if target != userID && !CanViewOthersTime(u.Role) {
+		common.Error(w, common.ErrForbidden)
+		return
+	}
+	today := startOfDay(time.Now())
+	from := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	to := from.AddDate(0, 0, 6)
+	for param, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		d, err := parseDay(q.Get(param))
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		if d != nil {
+			*dst = *d
+		}
+	}
+	ts, err := h.svc.Timesheet(r.Context(), wsID, target, from, to)
+	if err != nil {
+		common.Error(w, err)
+		return
+	}
+	common.OK(w, ts)
+}
+
+// contextUserID returns the caller's ID, replying 401 when there is none.
+func contextUserID(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	u := common.GetContextUser(r.Context())
+	if u == nil || u.UserID == "" {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(u.UserID)
+	if err != nil {
+		common.Error(w, common.ErrUnauthorized)
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// parseMinutes takes minutes, or duration (e.g. "1h30m") rounded up to whole minutes when given.
+func parseMinutes(minutes int, duration string) (int, error) {
+	if duration == "" {
+		return minutes, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: duration must be positive, e.g. 1h30m", common.ErrInvalidInput)
+	}
+	return int((d + time.Minute - 1) / time.Minute), nil
+}
+
+// parseDay parses a YYYY-MM-DD date; empty gives nil.
+func parseDay(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	d, err := time.Parse(dayLayout, v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: dates must be YYYY-MM-DD", common.ErrInvalidInput)
+	}
+	return &d, nil
+}
+
+// taskPath parses the workspace, project and task IDs from the route.
+func taskPath(r *http.Request) (wsID, pid, tid primitive.ObjectID, ok bool) {
+	var err error
+	if wsID, err = primitive.ObjectIDFromHex(r.PathValue("id")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if pid, err = primitive.ObjectIDFromHex(r.PathValue("pid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	if tid, err = primitive.ObjectIDFromHex(r.PathValue("tid")); err != nil {
+		return wsID, pid, tid, false
+	}
+	return wsID, pid, tid, true
+}
@@ -0,0 +1,86 @@
+package worklog
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Worklog is time a user spent on a task. Date is the day the work was done, at midnight UTC.
+type Worklog struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	WorkspaceID primitive.ObjectID `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+
+	Minutes int       `bson:"minutes" json:"minutes"`
+	Date    time.Time `bson:"date" json:"date"`
+	Note    string    `bson:"note" json:"note"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Timer is a running timer. A user has at most one; stopping it turns the elapsed time into a Worklog.
+type Timer struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	WorkspaceID primitive.ObjectID `bson:"workspace_id" json:"workspace_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	TaskID      primitive.ObjectID `bson:"task_id" json:"task_id"`
+	Note        string             `bson:"note" json:"note"`
+	StartedAt   time.Time          `bson:"started_at" json:"started_at"`
+}
+
+// Group is what Summary totals time by.
+type Group string
+
+const (
+	GroupTask    Group = "task"
+	GroupProject Group = "project"
+	GroupUser    Group = "user"
+)
+
+// groupFields maps each Group to the worklog field it groups on.
+var groupFields = map[Group]string{
+	GroupTask:    "task_id",
+	GroupProject: "project_id",
+	GroupUser:    "user_id",
+}
+
+// Filter narrows worklogs for Summary; nil fields mean no constraint. From and To are days, inclusive.
+type Filter struct {
+	ProjectID *primitive.ObjectID
+	TaskID    *primitive.ObjectID
+	UserID    *primitive.ObjectID
+	From      *time.Time
+	To        *time.Time
+}
+
+// Total is the time logged on one task, project or user.
+type Total struct {
+	ID      primitive.ObjectID `bson:"_id" json:"id"`
+	Minutes int                `bson:"minutes" json:"minutes"`
+	Entries int                `bson:"entries" json:"entries"`
+}
+
+// Day is one day of a timesheet.
+type Day struct {
+	Date    time.Time  `json:"date"`
+	Minutes int        `json:"minutes"`
+	Entries []*Worklog `json:"entries"`
+}
+
+// Timesheet is a user's logged time over a range of days: every day of the range (empty days
+// included), totals per task and the overall total.
+type Timesheet struct {
+	UserID       primitive.ObjectID `json:"user_id"`
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Days         []Day              `json:"days"`
+	Tasks        []Total            `json:"tasks"`
+	TotalMinutes int                `json:"total_minutes"`
+}
@@ -0,0 +1,16 @@
+package worklog
+
+import (
+	"planelite-backend/internal/common"
+)
+
+// CanEditWorklog: the author of a worklog or an ADMIN may change or delete it.
+func CanEditWorklog(role common.Role, isAuthor bool) bool {
+	return isAuthor || role == common.RoleAdmin
+}
+
+// CanViewOthersTime: admin and PROJECT_MANAGER can see the time and timesheets of other users;
+// everyone else only their own.
+func CanViewOthersTime(role common.Role) bool {
+	return role == common.RoleAdmin || role == common.RoleProjectManager
+}
@@ -0,0 +1,157 @@
+package worklog
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"planelite-backend/internal/common"
+)
+
+type Repository struct {
+	col    *mongo.Collection
+	timers *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{col: db.Collection("worklogs"), timers: db.Collection("timers")}
+}
+
+func (r *Repository) Create(ctx context.Context, w *Worklog) error {
+	result, err := r.col.InsertOne(ctx, w)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		w.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*Worklog, error) {
+	var w Worklog
+	if err := r.col.FindOne(ctx, bson.M{"_id": id}).Decode(&w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListByTask returns one page of the task's worklogs, most recent day first.
+func (r *Repository) ListByTask(ctx context.Context, taskID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Worklog], error) {
+	sort := bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}
+	return common.FindPage[Worklog](ctx, r.col, bson.M{"task_id": taskID}, sort, p)
+}
+
+// ListByUser returns the user's worklogs in the workspace on days from to to (inclusive), oldest first.
+func (r *Repository) ListByUser(ctx context.Context, workspaceID, userID primitive.ObjectID, from, to time.Time) ([]*Worklog, error) {
+	filter := bson.M{"workspace_id": workspaceID, "user_id": userID, "date": bson.M{"$gte": from, "$lte": to}}
+	cur, err := r.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []*Worklog{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Totals sums the minutes of the workspace's worklogs matching f, grouped by field, largest first.
+func (r *Repository) Totals(ctx context.Context, workspaceID primitive.ObjectID, f Filter, field string) ([]Total, error) {
+	match := bson.M{"workspace_id": workspaceID}
+	if f.ProjectID != nil {
+		match["project_id"] = *f.ProjectID
+	}
+	if f.TaskID != nil {
+		match["task_id"] = *f.TaskID
+	}
+	if f.UserID != nil {
+		match["user_id"] = *f.UserID
+	}
+	if f.From != nil || f.To != nil {
+		date := bson.M{}
+		if f.From != nil {
+			date["$gte"] = *f.From
+		}
+		if f.To != nil {
+			date["$lte"] = *f.To
+		}
+		match["date"] = date
+	}
+	cur, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "minutes": bson.M{"$sum": "$minutes"}, "entries": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "minutes", Value: -1}, {Key: "_id", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	out := []Total{}
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.col.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// DeleteByTask removes the worklogs and any running timers of a task.
+func (r *Repository) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	if _, err := r.timers.DeleteMany(ctx, bson.M{"task_id": taskID}); err != nil {
+		return err
+	}
+	_, err := r.col.DeleteMany(ctx, bson.M{"task_id": taskID})
+	return err
+}
+
+// SetProject records that the worklogs and timers of a task now belong to projectID.
+func (r *Repository) SetProject(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	if _, err := r.timers.UpdateMany(ctx, bson.M{"task_id": taskID}, bson.M{"$set": bson.M{"project_id": projectID}}); err != nil {
+		return err
+	}
+	_, err := r.col.UpdateMany(ctx, bson.M{"task_id": taskID}, bson.M{"$set": bson.M{"project_id": projectID}})
+	return err
+}
+
+// CreateTimer inserts a timer; the unique index on timers.user_id rejects a second one per user with a
+// duplicate key error.
+func (r *Repository) CreateTimer(ctx context.Context, t *Timer) error {
+	result, err := r.timers.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		t.ID = oid
+	}
+	return nil
+}
+
+func (r *Repository) FindTimer(ctx context.Context, userID primitive.ObjectID) (*Timer, error) {
+	var t Timer
+	if err := r.timers.FindOne(ctx, bson.M{"user_id": userID}).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TakeTimer removes and returns the user's timer, so that two concurrent stops log the time once.
+func (r *Repository) TakeTimer(ctx context.Context, userID primitive.ObjectID) (*Timer, error) {
+	var t Timer
+	if err := r.timers.FindOneAndDelete(ctx, bson.M{"user_id": userID}).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
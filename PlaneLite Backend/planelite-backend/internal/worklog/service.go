@@ -0,0 +1,297 @@
+package worklog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
+	"planelite-backend/internal/project"
+)
+
+const (
+	// MaxMinutes caps a single worklog at one day; longer stretches are logged as several entries.
+	MaxMinutes = 24 * 60
+	// maxTimesheetDays bounds the range of one timesheet.
+	maxTimesheetDays = 366
+)
+
+// TaskChecker verifies that a task exists in a project. Implemented by task.Service.
+type TaskChecker interface {
+	CheckInProject(ctx context.Context, projectID, id primitive.ObjectID) error
+}
+
+type Service struct {
+	repo     *Repository
+	tasks    TaskChecker
+	projects *project.Service
+}
+
+func NewService(repo *Repository, tasks TaskChecker, projects *project.Service) *Service {
+	return &Service{repo: repo, tasks: tasks, projects: projects}
+}
+
+// Input holds a new worklog. A nil Date means today (UTC).
+type Input struct {
+	Minutes int
+	Date    *time.Time
+	Note    string
+}
+
+// Patch lists the changes to a worklog; nil fields are left alone.
+type Patch struct {
+	Minutes *int
+	Date    *time.Time
+	Note    *string
+}
+
+// Log records time userID spent on a task.
+func (s *Service) Log(ctx context.Context, workspaceID, projectID, taskID, userID primitive.ObjectID, in Input) (*Worklog, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	if err := checkMinutes(in.Minutes); err != nil {
+		return nil, err
+	}
+	date := startOfDay(time.Now())
+	if in.Date != nil {
+		date = startOfDay(*in.Date)
+	}
+	now := time.Now()
+	w := &Worklog{
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		UserID:      userID,
+		Minutes:     in.Minutes,
+		Date:        date,
+		Note:        strings.TrimSpace(in.Note),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ListByTask returns one page of the task's worklogs, most recent day first.
+func (s *Service) ListByTask(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Worklog], error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTask(ctx, taskID, p)
+}
+
+// Update changes a worklog of the task. Only its author or an ADMIN may.
+func (s *Service) Update(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, role common.Role, p Patch) (*Worklog, error) {
+	w, err := s.editable(ctx, workspaceID, projectID, taskID, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{"updated_at": time.Now()}
+	if p.Minutes != nil {
+		if err := checkMinutes(*p.Minutes); err != nil {
+			return nil, err
+		}
+		set["minutes"] = *p.Minutes
+	}
+	if p.Date != nil {
+		set["date"] = startOfDay(*p.Date)
+	}
+	if p.Note != nil {
+		set["note"] = strings.TrimSpace(*p.Note)
+	}
+	if err := s.repo.Update(ctx, w.ID, set); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete removes a worklog of the task. Only its author or an ADMIN may.
+func (s *Service) Delete(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, role common.Role) error {
+	if _, err := s.editable(ctx, workspaceID, projectID, taskID, id, userID, role); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// StartTimer starts a timer on the task for userID. A user has one timer at a time; starting another
+// while one runs is a conflict.
+func (s *Service) StartTimer(ctx context.Context, workspaceID, projectID, taskID, userID primitive.ObjectID, note string) (*Timer, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	t := &Timer{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		ProjectID:   projectID,
+		TaskID:      taskID,
+		Note:        strings.TrimSpace(note),
+		StartedAt:   time.Now(),
+	}
+	if err := s.repo.CreateTimer(ctx, t); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("%w: a timer is already running; stop it first", common.ErrConflict)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// CurrentTimer returns the user's running timer in the workspace.
+func (s *Service) CurrentTimer(ctx context.Context, workspaceID, userID primitive.ObjectID) (*Timer, error) {
+	t, err := s.repo.FindTimer(ctx, userID)
+	if err != nil || t.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	return t, nil
+}
+
+// StopTimer stops the user's running timer and logs the elapsed time, rounded up to whole minutes and
+// capped at MaxMinutes, on the day the timer started. With discard the time is dropped and no worklog
+// is returned. note, when not empty, replaces the note given at start.
+func (s *Service) StopTimer(ctx context.Context, workspaceID, userID primitive.ObjectID, note string, discard bool) (*Worklog, error) {
+	if _, err := s.CurrentTimer(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.TakeTimer(ctx, userID)
+	if err != nil {
+		return nil, common.ErrNotFound
+	}
+	if discard {
+		return nil, nil
+	}
+	now := time.Now()
+	minutes := int((now.Sub(t.StartedAt) + time.Minute - 1) / time.Minute)
+	minutes = min(max(minutes, 1), MaxMinutes)
+	if note = strings.TrimSpace(note); note == "" {
+		note = t.Note
+	}
+	w := &Worklog{
+		WorkspaceID: t.WorkspaceID,
+		ProjectID:   t.ProjectID,
+		TaskID:      t.TaskID,
+		UserID:      userID,
+		Minutes:     minutes,
+		Date:        startOfDay(t.StartedAt),
+		Note:        note,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Summary totals the workspace's logged time matching f by task, project or user.
+func (s *Service) Summary(ctx context.Context, workspaceID primitive.ObjectID, group Group, f Filter) ([]Total, error) {
+	field, ok := groupFields[group]
+	if !ok {
+		return nil, fmt.Errorf("%w: group must be task, project or user", common.ErrInvalidInput)
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return nil, fmt.Errorf("%w: to must not be before from", common.ErrInvalidInput)
+	}
+	return s.repo.Totals(ctx, workspaceID, f, field)
+}
+
+// Timesheet returns the time userID logged in the workspace from one day to another, both included.
+func (s *Service) Timesheet(ctx context.Context, workspaceID, userID primitive.ObjectID, from, to time.Time) (*Timesheet, error) {
+	from, to = startOfDay(from), startOfDay(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to must not be before from", common.ErrInvalidInput)
+	}
+	if to.Sub(from) >= maxTimesheetDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a timesheet covers at most %d days", common.ErrInvalidInput, maxTimesheetDays)
+	}
+	list, err := s.repo.ListByUser(ctx, workspaceID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	ts := &Timesheet{UserID: userID, From: from, To: to, Days: []Day{}, Tasks: []Total{}}
+	byDay := map[time.Time][]*Worklog{}
+	byTask := map[primitive.ObjectID]int{}
+	for _, w := range list {
+		byDay[w.Date] = append(byDay[w.Date], w)
+		if _, ok := byTask[w.TaskID]; !ok {
+			byTask[w.TaskID] = len(ts.Tasks)
+			ts.Tasks = append(ts.Tasks, Total{ID: w.TaskID})
+		}
+		t := &ts.Tasks[byTask[w.TaskID]]
+		t.Minutes += w.Minutes
+		t.Entries++
+		ts.TotalMinutes += w.Minutes
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := Day{Date: d, Entries: byDay[d]}
+		if day.Entries == nil {
+			day.Entries = []*Worklog{}
+		}
+		for _, w := range day.Entries {
+			day.Minutes += w.Minutes
+		}
+		ts.Days = append(ts.Days, day)
+	}
+	return ts, nil
+}
+
+// DeleteByTask removes the worklogs and timers of a deleted task.
+func (s *Service) DeleteByTask(ctx context.Context, taskID primitive.ObjectID) error {
+	return s.repo.DeleteByTask(ctx, taskID)
+}
+
+// MoveTask follows a task into another project of the same workspace.
+func (s *Service) MoveTask(ctx context.Context, taskID, projectID primitive.ObjectID) error {
+	return s.repo.SetProject(ctx, taskID, projectID)
+}
+
+// checkTask verifies that the project is in the workspace and the task, live, in the project.
+func (s *Service) checkTask(ctx context.Context, workspaceID, projectID, taskID primitive.ObjectID) error {
+	p, err := s.projects.GetByID(ctx, projectID)
+	if err != nil || p.WorkspaceID != workspaceID {
+		return common.ErrNotFound
+	}
+	return s.tasks.CheckInProject(ctx, projectID, taskID)
+}
+
+// editable returns a worklog of the task that userID may change, checking the task, project and
+// workspace of the route.
+func (s *Service) editable(ctx context.Context, workspaceID, projectID, taskID, id, userID primitive.ObjectID, role common.Role) (*Worklog, error) {
+	if err := s.checkTask(ctx, workspaceID, projectID, taskID); err != nil {
+		return nil, err
+	}
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	if w.TaskID != taskID || w.ProjectID != projectID || w.WorkspaceID != workspaceID {
+		return nil, common.ErrNotFound
+	}
+	if !CanEditWorklog(role, w.UserID == userID) {
+		return nil, common.ErrForbidden
+	}
+	return w, nil
+}
+
+func checkMinutes(m int) error {
+	if m <= 0 || m > MaxMinutes {
+		return fmt.Errorf("%w: minutes must be between 1 and %d", common.ErrInvalidInput, MaxMinutes)
+	}
+	return nil
+}
+
+// startOfDay truncates t to midnight UTC of its day, the form in which worklog dates are stored.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
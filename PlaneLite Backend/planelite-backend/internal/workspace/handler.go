@@ -168,7 +168,8 @@ func (h *Handler) ApproveMember(w http.ResponseWriter, r *http.Request) {
 	common.NoContent(w)
 }
 
-// ListMembers handles GET /workspaces/:id/members.
+// ListMembers handles GET /workspaces/:id/members. It returns every membership unless paging is
+// requested (see common.WantsPage).
 func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		common.Error(w, common.ErrBadRequest)
@@ -179,6 +180,20 @@ func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
 		common.Error(w, common.ErrBadRequest)
 		return
 	}
+	if common.WantsPage(r) {
+		p, err := common.ParseListParams(r)
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		page, err := h.svc.ListMembersPage(r.Context(), wsID, p)
+		if err != nil {
+			common.Error(w, err)
+			return
+		}
+		common.OK(w, page)
+		return
+	}
 	list, err := h.svc.ListMembers(r.Context(), wsID)
 	if err != nil {
 		common.Error(w, err)
@@ -6,6 +6,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"planelite-backend/internal/common"
 )
 
 // MembershipRepository handles membership collection.
@@ -76,3 +77,8 @@ func (r *MembershipRepository) ListByWorkspace(ctx context.Context, workspaceID
 	}
 	return out, nil
 }
+
+// ListPage returns one page of the workspace's memberships, oldest first.
+func (r *MembershipRepository) ListPage(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Membership], error) {
+	return common.FindPage[Membership](ctx, r.col, bson.M{"workspace_id": workspaceID}, bson.D{{Key: "_id", Value: 1}}, p)
+}
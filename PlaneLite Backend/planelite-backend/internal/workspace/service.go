@@ -101,3 +101,8 @@ func (s *Service) ListByAdminID(ctx context.Context, adminID primitive.ObjectID)
 func (s *Service) ListMembers(ctx context.Context, workspaceID primitive.ObjectID) ([]*Membership, error) {
 	return s.memRepo.ListByWorkspace(ctx, workspaceID)
 }
+
+// ListMembersPage returns one page of memberships for a workspace.
+func (s *Service) ListMembersPage(ctx context.Context, workspaceID primitive.ObjectID, p common.ListParams) (*common.ListPage[*Membership], error) {
+	return s.memRepo.ListPage(ctx, workspaceID, p)
+}